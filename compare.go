@@ -0,0 +1,432 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"cmp"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+)
+
+// Fingerprint returns a hash of the nodes and edges of the graph. Equal graphs
+// have equal fingerprints, regardless of the order in which nodes and edges
+// were added, and different graphs have different fingerprints with high
+// probability. Keys are hashed by their representation as formatted by fmt's
+// %#v verb, so keys with the same representation hash the same. Fingerprints
+// are stable across processes.
+func (g *Graph[Key]) Fingerprint() uint64 {
+	// We combine the hashes of the nodes and edges by adding them, so the
+	// result does not depend on the order of the map iteration.
+	var fp uint64
+	for from, e := range g.nodes {
+		f := hashKey(from)
+		fp += mix(f)
+		for to := range e {
+			fp += mix(f*31 + hashKey(to) + 1)
+		}
+	}
+	return fp
+}
+
+// StructuralHashes returns a hash for every node that depends on its key and
+// the hashes of the nodes it has an edge to, and so on the keys and edges of
+// all nodes it can reach, but not on the rest of the graph. A node keeps its
+// hash as long as it and everything it depends on are unchanged, for example
+// to skip rebuilding a module in a content-addressed build cache. Keys are
+// hashed like in Fingerprint, so hashes are stable across processes. It
+// returns ErrCycle if the graph has a cycle.
+func (g *Graph[Key]) StructuralHashes() (map[Key]uint64, error) {
+	order, err := g.Sort()
+	if err != nil {
+		return nil, err
+	}
+
+	// Every node comes after the nodes it has an edge to in the reversed
+	// order, so their hashes are known first. They are combined by adding
+	// them, so the result does not depend on the order of the map iteration.
+	hashes := make(map[Key]uint64, len(order))
+	for _, n := range slices.Backward(order) {
+		var deps uint64
+		for m := range g.nodes[n] {
+			deps += mix(hashes[m])
+		}
+		hashes[n] = mix(hashKey(n)*31 + mix(deps+1))
+	}
+
+	return hashes, nil
+}
+
+// ChangedSince reports whether the fingerprint of the graph differs from fp.
+// It computes the fingerprint, so it takes O(n) time for n = [number of
+// nodes] + [number of edges]. Use Dirty to check for changes without hashing.
+func (g *Graph[Key]) ChangedSince(fp uint64) bool {
+	return g.Fingerprint() != fp
+}
+
+// Canonical returns a text representation of the graph that only depends on
+// its nodes and edges, for example to store a graph in a file that is compared
+// across versions. Every edge is written as from->to on a line of its own, and
+// every node without any edges is written as just its key, using fmt's %v
+// verb. The lines are sorted lexicographically, and every line ends with a
+// newline.
+func (g *Graph[Key]) Canonical() string {
+	connected := make(map[Key]bool, len(g.nodes))
+
+	var lines []string
+	for from, e := range g.nodes {
+		for to := range e {
+			lines = append(lines, fmt.Sprintf("%v->%v\n", from, to))
+			connected[from] = true
+			connected[to] = true
+		}
+	}
+
+	for k := range g.nodes {
+		if !connected[k] {
+			lines = append(lines, fmt.Sprintf("%v\n", k))
+		}
+	}
+
+	slices.Sort(lines)
+
+	return strings.Join(lines, "")
+}
+
+// Diff returns how other differs from the graph, in a deterministic order:
+// the nodes and edges of other that the graph does not have, and the nodes and
+// edges of the graph that other does not have. Edges of removed nodes are
+// part of removedEdges, and edges of added nodes are part of addedEdges, so
+// applying all four to the graph gives other.
+func (g *Graph[Key]) Diff(other *Graph[Key]) (addedNodes, removedNodes []Key, addedEdges, removedEdges [][2]Key) {
+	// diff returns the nodes and edges of a that b does not have.
+	diff := func(a, b *Graph[Key]) ([]Key, [][2]Key) {
+		var nodes []Key
+		var edges [][2]Key
+		for from, e := range a.nodes {
+			if _, ok := b.nodes[from]; !ok {
+				nodes = append(nodes, from)
+			}
+			for to := range e {
+				if !b.nodes[from][to] {
+					edges = append(edges, [2]Key{from, to})
+				}
+			}
+		}
+		sortKeys(nodes)
+		sortEdges(edges)
+		return nodes, edges
+	}
+
+	addedNodes, addedEdges = diff(other, g)
+	removedNodes, removedEdges = diff(g, other)
+
+	return addedNodes, removedNodes, addedEdges, removedEdges
+}
+
+// ApplyAndDiff calls mutate with the graph, and returns how the graph changed,
+// like Diff between a copy of the graph from before the call and the graph
+// after it. This is what a view of the graph needs to update only what changed
+// after a batch of changes. A node or edge that is removed and added again
+// within mutate is not part of the result. The copy takes O(n) time and
+// memory for n = [number of nodes] + [number of edges].
+func (g *Graph[Key]) ApplyAndDiff(mutate func(*Graph[Key])) (addedNodes, removedNodes []Key, addedEdges, removedEdges [][2]Key) {
+	before := g.Copy()
+	mutate(g)
+	return before.Diff(g)
+}
+
+// IsomorphicTo reports whether the graph has the same structure as other,
+// regardless of the keys: whether there is a one-to-one mapping between their
+// nodes such that a -> b is an edge of the graph if and only if the mapping of
+// a has an edge to the mapping of b in other.
+//
+// Nodes are first grouped by their in-degree, out-degree and self-loop, and
+// these groups are refined by the groups of their neighbors until they do not
+// change anymore. Graphs with different group sizes are not isomorphic. Then
+// it tries to map every node to a node of the same group, backtracking when
+// the edges do not match. This is fast for most graphs, but can take time
+// exponential in the number of nodes for very regular graphs, so it is only
+// feasible for graphs of modest size.
+func (g *Graph[Key]) IsomorphicTo(other *Graph[Key]) bool {
+	edges := func(g *Graph[Key]) int {
+		n := 0
+		for _, e := range g.nodes {
+			n += len(e)
+		}
+		return n
+	}
+
+	if len(g.nodes) != len(other.nodes) || edges(g) != edges(other) {
+		return false
+	}
+
+	ga, gb := g.Reverse(), other.Reverse()
+	ca, cb := g.initialColors(ga), other.initialColors(gb)
+
+	// Refining the colors of both graphs in the same way gives nodes that
+	// can be mapped to each other the same color.
+	for {
+		if !sameColors(ca, cb) {
+			return false
+		}
+
+		na, nb := g.refineColors(ga, ca), other.refineColors(gb, cb)
+		if distinctColors(na) <= distinctColors(ca) {
+			break
+		}
+		ca, cb = na, nb
+	}
+
+	// The nodes of other with every color, which are the candidates for the
+	// nodes of the graph with that color.
+	candidates := make(map[uint64][]Key)
+	for _, k := range other.keys() {
+		candidates[cb[k]] = append(candidates[cb[k]], k)
+	}
+
+	// We map the nodes of the rarest colors first, and continue with their
+	// neighbors, so that every next node is constrained by the nodes that are
+	// already mapped.
+	keys := g.keys()
+	slices.SortStableFunc(keys, func(a, b Key) int {
+		return len(candidates[ca[a]]) - len(candidates[ca[b]])
+	})
+
+	u := g.undirected()
+	order := make([]Key, 0, len(keys))
+	seen := make(map[Key]bool, len(keys))
+	for _, k := range keys {
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		order = append(order, k)
+
+		for i := len(order) - 1; i < len(order); i++ {
+			var next []Key
+			for m := range u[order[i]] {
+				if !seen[m] {
+					seen[m] = true
+					next = append(next, m)
+				}
+			}
+			sortKeys(next)
+			order = append(order, next...)
+		}
+	}
+
+	forward := make(map[Key]Key, len(keys))
+	backward := make(map[Key]Key, len(keys))
+
+	// consistent reports whether n can be mapped to c, given the nodes that
+	// are already mapped.
+	consistent := func(n, c Key) bool {
+		if g.nodes[n][n] != other.nodes[c][c] {
+			return false
+		}
+		for m := range g.nodes[n] {
+			if mc, ok := forward[m]; ok && !other.nodes[c][mc] {
+				return false
+			}
+		}
+		for m := range ga.nodes[n] {
+			if mc, ok := forward[m]; ok && !other.nodes[mc][c] {
+				return false
+			}
+		}
+		for m := range other.nodes[c] {
+			if mn, ok := backward[m]; ok && !g.nodes[n][mn] {
+				return false
+			}
+		}
+		for m := range gb.nodes[c] {
+			if mn, ok := backward[m]; ok && !g.nodes[mn][n] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var match func(i int) bool
+	match = func(i int) bool {
+		if i == len(order) {
+			return true
+		}
+
+		n := order[i]
+		for _, c := range candidates[ca[n]] {
+			if _, ok := backward[c]; ok || !consistent(n, c) {
+				continue
+			}
+
+			forward[n], backward[c] = c, n
+			if match(i + 1) {
+				return true
+			}
+			delete(forward, n)
+			delete(backward, c)
+		}
+
+		return false
+	}
+
+	return match(0)
+}
+
+// Canonicalize returns a copy of the graph in which the nodes are numbered
+// from 0 to the number of nodes minus one, based only on the structure of the
+// graph, so isomorphic graphs usually canonicalize to equal graphs, for
+// example to deduplicate graphs that only differ in their keys.
+//
+// Like IsomorphicTo, it groups nodes by their degrees and refines these groups
+// by the groups of their neighbors. While some nodes are still in the same
+// group, the node with the smallest key of the first such group is put in a
+// group of its own, and the groups are refined again. The nodes are numbered
+// in the order of their final groups. In most graphs, nodes that remain in the
+// same group are symmetric, so the choice does not matter. In highly regular
+// graphs, however, nodes that are not symmetric can remain in the same group,
+// like the nodes of a cycle of six nodes and of a cycle of three nodes, and
+// then isomorphic graphs with different keys can get different numbers. Use
+// IsomorphicTo to compare such graphs. It takes O(n * r * (n + m)) time in
+// the worst case, for n = [number of nodes], m = [number of edges] and
+// r = [number of refinements].
+func (g *Graph[Key]) Canonicalize() *Graph[int] {
+	r := g.Reverse()
+	colors := g.stableColors(r, g.initialColors(r))
+
+	for {
+		// The members of every color, to find the first color with more
+		// than one member.
+		members := make(map[uint64][]Key, len(colors))
+		for k, c := range colors {
+			members[c] = append(members[c], k)
+		}
+
+		tied, found := uint64(0), false
+		for c, m := range members {
+			if len(m) > 1 && (!found || c < tied) {
+				tied, found = c, true
+			}
+		}
+		if !found {
+			break
+		}
+
+		m := members[tied]
+		sortKeys(m)
+		colors[m[0]] = mix(colors[m[0]] + 1)
+		colors = g.stableColors(r, colors)
+	}
+
+	keys := g.keys()
+	slices.SortFunc(keys, func(a, b Key) int {
+		return cmp.Compare(colors[a], colors[b])
+	})
+
+	label := make(map[Key]int, len(keys))
+	c := New[int]()
+	for i, k := range keys {
+		label[k] = i
+		c.node(i)
+	}
+	for from, e := range g.nodes {
+		for to := range e {
+			c.link(label[from], label[to])
+		}
+	}
+
+	return c
+}
+
+// initialColors returns a color for every node that only depends on its
+// in-degree, out-degree and whether it has a self-loop. The reverse of the
+// graph provides the incoming edges.
+func (g *Graph[Key]) initialColors(reverse *Graph[Key]) map[Key]uint64 {
+	colors := make(map[Key]uint64, len(g.nodes))
+	for k, e := range g.nodes {
+		c := uint64(len(e))<<32 | uint64(len(reverse.nodes[k]))<<1
+		if e[k] {
+			c |= 1
+		}
+		colors[k] = mix(c)
+	}
+	return colors
+}
+
+// refineColors returns a new color for every node that depends on its current
+// color and the colors of the nodes it has an edge to and from. Nodes with
+// different colors keep different colors, with high probability.
+func (g *Graph[Key]) refineColors(reverse *Graph[Key], colors map[Key]uint64) map[Key]uint64 {
+	refined := make(map[Key]uint64, len(colors))
+	for k, e := range g.nodes {
+		// The colors of the neighbors are combined by adding them, so the
+		// result does not depend on the order of the map iteration.
+		var out, in uint64
+		for m := range e {
+			out += mix(colors[m])
+		}
+		for m := range reverse.nodes[k] {
+			in += mix(colors[m] + 1)
+		}
+		refined[k] = mix(colors[k]*31 + mix(out) + mix(in+2))
+	}
+	return refined
+}
+
+// stableColors refines the colors until the number of distinct colors does
+// not grow anymore.
+func (g *Graph[Key]) stableColors(reverse *Graph[Key], colors map[Key]uint64) map[Key]uint64 {
+	for {
+		refined := g.refineColors(reverse, colors)
+		if distinctColors(refined) <= distinctColors(colors) {
+			return colors
+		}
+		colors = refined
+	}
+}
+
+// distinctColors returns the number of distinct colors.
+func distinctColors[Key comparable](colors map[Key]uint64) int {
+	seen := make(map[uint64]bool)
+	for _, c := range colors {
+		seen[c] = true
+	}
+	return len(seen)
+}
+
+// sameColors reports whether two colorings use every color equally often.
+func sameColors[Key comparable](a, b map[Key]uint64) bool {
+	count := make(map[uint64]int)
+	for _, c := range a {
+		count[c]++
+	}
+	for _, c := range b {
+		count[c]--
+	}
+	for _, n := range count {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hashKey returns the FNV-1a hash of the representation of a key.
+func hashKey[Key comparable](k Key) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%#v", k)
+	return h.Sum64()
+}
+
+// mix scrambles the bits of a hash, using the finalizer of SplitMix64, so
+// hashes combined by adding them do not cancel out.
+func mix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
@@ -0,0 +1,339 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	g := New[string]()
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+
+	// The same graph built in another order has the same fingerprint.
+	h := New[string]()
+	h.Node("c")
+	h.Edge("b", "c")
+	h.Edge("a", "b")
+
+	if g.Fingerprint() != h.Fingerprint() {
+		t.Error("expected equal fingerprints")
+	}
+
+	fp := g.Fingerprint()
+
+	if g.ChangedSince(fp) {
+		t.Error("expected no change")
+	}
+
+	// Reversing an edge changes the fingerprint.
+	delete(g.nodes["a"], "b")
+	g.Edge("b", "a")
+
+	if !g.ChangedSince(fp) {
+		t.Error("expected a change")
+	}
+}
+
+func TestStructuralHashes(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: app -> lib -> util
+	// and tool -> util.
+	g.Edge("app", "lib")
+	g.Edge("lib", "util")
+	g.Edge("tool", "util")
+
+	// The same dependencies of lib in another graph.
+	h := New[string]()
+	h.Edge("lib", "util")
+	h.Edge("cli", "lib")
+
+	before, err := g.StructuralHashes()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	other, err := h.StructuralHashes()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if before["lib"] != other["lib"] || before["util"] != other["util"] {
+		t.Error("expected equal hashes for equal dependencies")
+	}
+
+	if before["app"] == before["lib"] || before["tool"] == before["app"] {
+		t.Error("expected different hashes for different nodes")
+	}
+
+	// A new dependency of lib changes the hashes of lib and app only.
+	g.Edge("lib", "log")
+
+	after, err := g.StructuralHashes()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, k := range []string{"app", "lib"} {
+		if after[k] == before[k] {
+			t.Errorf("expected the hash of %v to change", k)
+		}
+	}
+	for _, k := range []string{"tool", "util"} {
+		if after[k] != before[k] {
+			t.Errorf("expected the hash of %v not to change", k)
+		}
+	}
+
+	g.Edge("util", "app")
+
+	if _, err := g.StructuralHashes(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestDirty(t *testing.T) {
+	g := New[string]()
+
+	if g.Dirty() {
+		t.Error("expected a new graph to be clean")
+	}
+
+	g.Edge("a", "b")
+
+	if !g.Dirty() {
+		t.Error("expected the graph to be dirty after adding an edge")
+	}
+
+	g.MarkClean()
+
+	// Adding nodes and edges that already exist does not change the graph.
+	g.Node("a")
+	g.Edge("a", "b")
+	g.Add("a", []string{"b"})
+
+	if g.Dirty() {
+		t.Error("expected the graph to be clean")
+	}
+
+	for _, c := range []struct {
+		name   string
+		mutate func()
+	}{
+		{"Node", func() { g.Node("c") }},
+		{"Edge", func() { g.Edge("b", "a") }},
+		{"Add", func() { g.Add("a", []string{"d"}) }},
+		{"Rename", func() { _ = g.Rename("d", "e") }},
+	} {
+		g.MarkClean()
+		c.mutate()
+
+		if !g.Dirty() {
+			t.Errorf("expected the graph to be dirty after %v", c.name)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	g := New[int]()
+	g.Edge(10, 2)
+	g.Edge(1, 10)
+	g.Edge(1, 2)
+	g.Node(3)
+
+	// The lines are sorted as text, so 1->10 comes before 1->2.
+	expected := "1->10\n1->2\n10->2\n3\n"
+
+	if s := g.Canonical(); s != expected {
+		t.Errorf("expected %q, got %q", expected, s)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c and
+	// a -> d. The other graph drops d, adds e and replaces b -> c by c -> b.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("a", "d")
+
+	other := New[string]()
+	other.Edge("a", "b")
+	other.Edge("c", "b")
+	other.Edge("e", "a")
+
+	addedNodes, removedNodes, addedEdges, removedEdges := g.Diff(other)
+
+	if !reflect.DeepEqual(addedNodes, []string{"e"}) {
+		t.Errorf("expected [e], got %v", addedNodes)
+	}
+
+	if !reflect.DeepEqual(removedNodes, []string{"d"}) {
+		t.Errorf("expected [d], got %v", removedNodes)
+	}
+
+	if expected := [][2]string{{"c", "b"}, {"e", "a"}}; !reflect.DeepEqual(addedEdges, expected) {
+		t.Errorf("expected %v, got %v", expected, addedEdges)
+	}
+
+	if expected := [][2]string{{"a", "d"}, {"b", "c"}}; !reflect.DeepEqual(removedEdges, expected) {
+		t.Errorf("expected %v, got %v", expected, removedEdges)
+	}
+
+	addedNodes, removedNodes, addedEdges, removedEdges = g.Diff(g.Copy())
+
+	if addedNodes != nil || removedNodes != nil || addedEdges != nil || removedEdges != nil {
+		t.Errorf("expected no differences, got %v %v %v %v", addedNodes, removedNodes, addedEdges, removedEdges)
+	}
+}
+
+func TestApplyAndDiff(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c and
+	// a -> d.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("a", "d")
+
+	addedNodes, removedNodes, addedEdges, removedEdges := g.ApplyAndDiff(func(g *Graph[string]) {
+		g.RemoveMatching(func(k string) bool { return k == "d" })
+		g.Edge("c", "e")
+		g.Edge("x", "a")
+		g.RemoveMatching(func(k string) bool { return k == "x" })
+	})
+
+	if !reflect.DeepEqual(addedNodes, []string{"e"}) {
+		t.Errorf("expected [e], got %v", addedNodes)
+	}
+
+	if !reflect.DeepEqual(removedNodes, []string{"d"}) {
+		t.Errorf("expected [d], got %v", removedNodes)
+	}
+
+	if expected := [][2]string{{"c", "e"}}; !reflect.DeepEqual(addedEdges, expected) {
+		t.Errorf("expected %v, got %v", expected, addedEdges)
+	}
+
+	if expected := [][2]string{{"a", "d"}}; !reflect.DeepEqual(removedEdges, expected) {
+		t.Errorf("expected %v, got %v", expected, removedEdges)
+	}
+
+	if _, ok := g.nodes["e"]; !ok || g.nodes["a"]["d"] {
+		t.Errorf("expected the changes to be applied to the graph")
+	}
+}
+
+func TestIsomorphicTo(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> d,
+	// a -> c -> d, d -> d and an isolated node e.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "d")
+	g.Edge("c", "d")
+	g.Edge("d", "d")
+	g.Node("e")
+
+	// The same structure, with other keys.
+	h := New[string]()
+	h.Edge("z", "y")
+	h.Edge("z", "x")
+	h.Edge("x", "w")
+	h.Edge("y", "w")
+	h.Edge("w", "w")
+	h.Node("v")
+
+	if !g.IsomorphicTo(h) || !h.IsomorphicTo(g) {
+		t.Errorf("expected the graphs to be isomorphic")
+	}
+
+	// Moving the self-loop keeps all degrees but one.
+	h.Edge("v", "v")
+	h.Node("w")
+	delete(h.nodes["w"], "w")
+
+	if g.IsomorphicTo(h) {
+		t.Errorf("expected the graphs not to be isomorphic")
+	}
+
+	// A cycle of six nodes and two cycles of three nodes have the same
+	// degrees everywhere, so only backtracking can tell them apart.
+	six, three := New[int](), New[int]()
+	for i := 0; i < 6; i++ {
+		six.Edge(i, (i+1)%6)
+		three.Edge(i, i/3*3+(i+1)%3)
+	}
+
+	if six.IsomorphicTo(three) {
+		t.Errorf("expected the cycles not to be isomorphic")
+	}
+
+	// Renumbering the nodes of a cycle keeps it isomorphic.
+	renumbered := New[int]()
+	for i := 0; i < 6; i++ {
+		renumbered.Edge(i*5%6, (i+1)*5%6)
+	}
+
+	if !six.IsomorphicTo(renumbered) {
+		t.Errorf("expected the cycles to be isomorphic")
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c,
+	// a -> c, c -> d, d -> d and an isolated node e.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("a", "c")
+	g.Edge("c", "d")
+	g.Edge("d", "d")
+	g.Node("e")
+
+	// The same structure, with other keys.
+	h := New[string]()
+	h.Node("p")
+	h.Edge("z", "y")
+	h.Edge("y", "x")
+	h.Edge("z", "x")
+	h.Edge("x", "q")
+	h.Edge("q", "q")
+
+	cg, ch := g.Canonicalize(), h.Canonicalize()
+
+	if !reflect.DeepEqual(cg.nodes, ch.nodes) {
+		t.Errorf("expected equal graphs, got %v and %v", cg.nodes, ch.nodes)
+	}
+
+	if len(cg.nodes) != 5 {
+		t.Errorf("expected 5 nodes, got %v", len(cg.nodes))
+	}
+	for i := 0; i < 5; i++ {
+		if _, ok := cg.nodes[i]; !ok {
+			t.Errorf("expected node %v", i)
+		}
+	}
+
+	// Symmetric nodes, like those of a cycle, are tied until one of them is
+	// chosen, but every choice gives the same result.
+	a, b := New[int](), New[int]()
+	for i := 0; i < 4; i++ {
+		a.Edge(i, (i+1)%4)
+		b.Edge(10*i, 10*((i+3)%4))
+	}
+
+	if ca, cb := a.Canonicalize(), b.Canonicalize(); !reflect.DeepEqual(ca.nodes, cb.nodes) {
+		t.Errorf("expected equal graphs, got %v and %v", ca.nodes, cb.nodes)
+	}
+}
@@ -0,0 +1,371 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// FeedbackArcSet returns a set of edges whose removal makes the graph acyclic,
+// in a deterministic order. The set is approximately minimal: it is computed
+// with the greedy heuristic of Eades, Lin and Smyth, which is not guaranteed
+// to find the smallest possible set (finding that is NP-hard). It returns nil
+// for an acyclic graph. Self-loops are always part of the set.
+//
+// The heuristic builds an ordering of the nodes by repeatedly moving sinks to
+// the end and sources to the start of the ordering. When there are neither,
+// it moves the node with the largest difference between its out-degree and
+// in-degree to the start, preferring the smallest key. Every edge pointing
+// backwards in the resulting ordering is part of the feedback arc set. The
+// sinks, sources and differences are kept in heaps, so it takes
+// O(n * log(n)) time for n = [number of nodes] + [number of edges].
+func (g *Graph[Key]) FeedbackArcSet() [][2]Key {
+	// We need a copy of the graph and its reverse, so we can remove nodes
+	// from them while keeping track of the in-degree and out-degree of the
+	// remaining nodes.
+	out := g.Copy()
+	in := g.Reverse()
+
+	// Self-loops do not affect the ordering, so we ignore them from the
+	// start. They are added to the result at the end.
+	for k, e := range out.nodes {
+		delete(e, k)
+		delete(in.nodes[k], k)
+	}
+
+	// The sinks and sources that are found, by key. A node can be added to
+	// both, or be removed in the meantime, so it is checked when taken.
+	byKey := func(a, b Key) bool { return compareKeys(a, b) < 0 }
+	sinks := &keyHeap[Key]{less: byKey}
+	sources := &keyHeap[Key]{less: byKey}
+
+	// The difference between the out-degree and in-degree of every node,
+	// largest first. A candidate is outdated once the difference of its node
+	// changed, a newer candidate has been pushed then.
+	type candidate struct {
+		key   Key
+		delta int
+	}
+	deltas := &keyHeap[candidate]{less: func(a, b candidate) bool {
+		if a.delta != b.delta {
+			return a.delta > b.delta
+		}
+		return compareKeys(a.key, b.key) < 0
+	}}
+
+	// update records the current degrees of a remaining node.
+	update := func(n Key) {
+		if len(out.nodes[n]) == 0 {
+			heap.Push(sinks, n)
+		} else if len(in.nodes[n]) == 0 {
+			heap.Push(sources, n)
+		}
+		heap.Push(deltas, candidate{n, len(out.nodes[n]) - len(in.nodes[n])})
+	}
+
+	for k := range out.nodes {
+		update(k)
+	}
+
+	// remove removes node n from the working graphs, and updates its
+	// neighbors.
+	remove := func(n Key) {
+		succ, pred := out.nodes[n], in.nodes[n]
+		delete(out.nodes, n)
+		delete(in.nodes, n)
+
+		for m := range succ {
+			delete(in.nodes[m], n)
+			update(m)
+		}
+		for m := range pred {
+			delete(out.nodes[m], n)
+			update(m)
+		}
+	}
+
+	// The start and end of the ordering. The end is constructed backwards.
+	var start, end []Key
+
+	for len(out.nodes) > 0 {
+		if sinks.Len() > 0 {
+			n := heap.Pop(sinks).(Key)
+			if e, ok := out.nodes[n]; ok && len(e) == 0 {
+				end = append(end, n)
+				remove(n)
+			}
+			continue
+		}
+
+		if sources.Len() > 0 {
+			n := heap.Pop(sources).(Key)
+			if e, ok := in.nodes[n]; ok && len(e) == 0 {
+				start = append(start, n)
+				remove(n)
+			}
+			continue
+		}
+
+		// All remaining nodes are on or between cycles. We greedily pick the
+		// node with the largest difference between out-degree and in-degree.
+		c := heap.Pop(deltas).(candidate)
+		if e, ok := out.nodes[c.key]; ok && len(e)-len(in.nodes[c.key]) == c.delta {
+			start = append(start, c.key)
+			remove(c.key)
+		}
+	}
+
+	// The position of each node in the ordering start + reverse(end).
+	pos := make(map[Key]int, len(g.nodes))
+	for i, n := range start {
+		pos[n] = i
+	}
+	for i, n := range end {
+		pos[n] = len(start) + len(end) - 1 - i
+	}
+
+	// Every edge that points backwards in the ordering, including self-loops,
+	// is a feedback edge.
+	var arcs [][2]Key
+	for from, e := range g.nodes {
+		for to := range e {
+			if pos[to] <= pos[from] {
+				arcs = append(arcs, [2]Key{from, to})
+			}
+		}
+	}
+
+	sortEdges(arcs)
+
+	return arcs
+}
+
+// BackEdges returns the edges that point from a node to one of its ancestors
+// in a depth-first search over the whole graph, including self-loops. Every
+// cycle contains at least one back edge, so removing all of them makes the
+// graph acyclic. The search visits the nodes and their edges in a
+// deterministic order, so the result is the same for equal graphs. It returns
+// nil for an acyclic graph.
+func (g *Graph[Key]) BackEdges() [][2]Key {
+	// The colors of the depth-first search: white nodes have not been
+	// visited, gray nodes are on the current path and black nodes are done.
+	const (
+		white = iota
+		gray
+		black
+	)
+
+	color := make(map[Key]int, len(g.nodes))
+	var back [][2]Key
+
+	var visit func(n Key)
+	visit = func(n Key) {
+		color[n] = gray
+
+		next := make([]Key, 0, len(g.nodes[n]))
+		for m := range g.nodes[n] {
+			next = append(next, m)
+		}
+		sortKeys(next)
+
+		for _, m := range next {
+			switch color[m] {
+			case white:
+				visit(m)
+			case gray:
+				back = append(back, [2]Key{n, m})
+			}
+		}
+
+		color[n] = black
+	}
+
+	for _, k := range g.keys() {
+		if color[k] == white {
+			visit(k)
+		}
+	}
+
+	return back
+}
+
+// CycleInfo describes a strongly connected component of a graph that contains
+// a cycle, see CycleReport.
+type CycleInfo[Key comparable] struct {
+	// Nodes are the nodes of the component, in a deterministic order.
+	Nodes []Key
+
+	// Edges are the edges between the nodes of the component, in a
+	// deterministic order. Every one of them is part of a cycle.
+	Edges [][2]Key
+}
+
+// CycleReport describes every cycle in the graph at once, so they can all be
+// fixed in one pass. It returns one CycleInfo for every strongly connected
+// component with more than one node or with a self-loop, ordered by their
+// first node, together with ErrCycle. It returns nil and no error for an
+// acyclic graph.
+func (g *Graph[Key]) CycleReport() ([]CycleInfo[Key], error) {
+	components, id := g.condensation()
+
+	// The edges within each component. A component of a single node only has
+	// an internal edge if it has a self-loop.
+	internal := make([][][2]Key, len(components))
+	for from, e := range g.nodes {
+		for to := range e {
+			if c := id[from]; c == id[to] {
+				internal[c] = append(internal[c], [2]Key{from, to})
+			}
+		}
+	}
+
+	var report []CycleInfo[Key]
+	for i, c := range components {
+		if len(internal[i]) == 0 {
+			continue
+		}
+
+		nodes := slices.Clone(c)
+		sortKeys(nodes)
+		sortEdges(internal[i])
+		report = append(report, CycleInfo[Key]{Nodes: nodes, Edges: internal[i]})
+	}
+
+	if len(report) == 0 {
+		return nil, nil
+	}
+
+	slices.SortFunc(report, func(a, b CycleInfo[Key]) int {
+		return compareKeys(a.Nodes[0], b.Nodes[0])
+	})
+
+	return report, ErrCycle
+}
+
+// MutualEdges returns the pairs of nodes that have an edge to each other,
+// which are the cycles of two nodes. Every pair is returned once, with the
+// smaller key first, and the pairs are in a deterministic order. Self-loops
+// are not included.
+func (g *Graph[Key]) MutualEdges() [][2]Key {
+	// The pairs already found. Distinct keys can compare as equal, for
+	// example keys of an interface type with the same representation, so
+	// we cannot only keep the direction from the smaller key.
+	seen := make(map[[2]Key]bool)
+
+	var mutual [][2]Key
+	for from, e := range g.nodes {
+		for to := range e {
+			if from == to || !g.nodes[to][from] || seen[[2]Key{to, from}] {
+				continue
+			}
+			seen[[2]Key{from, to}] = true
+
+			if compareKeys(from, to) > 0 {
+				mutual = append(mutual, [2]Key{to, from})
+			} else {
+				mutual = append(mutual, [2]Key{from, to})
+			}
+		}
+	}
+
+	sortEdges(mutual)
+
+	return mutual
+}
+
+// ShortestCycleThrough returns the nodes of a shortest cycle through a node,
+// starting with the node itself, where the last node has an edge back to it.
+// For a self-loop, the cycle is only the node. It returns false if the node is
+// not on a cycle or does not exist. The search is a breadth-first search from
+// the node that stops at the first edge back to it.
+func (g *Graph[Key]) ShortestCycleThrough(key Key) ([]Key, bool) {
+	key = g.key(key)
+	if _, ok := g.nodes[key]; !ok {
+		return nil, false
+	}
+
+	// The node from which every visited node was reached.
+	parent := map[Key]Key{key: key}
+	queue := []Key{key}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		if g.nodes[n][key] {
+			var cycle []Key
+			for ; n != key; n = parent[n] {
+				cycle = append(cycle, n)
+			}
+			cycle = append(cycle, key)
+			slices.Reverse(cycle)
+			return cycle, true
+		}
+
+		for m := range g.nodes[n] {
+			if _, ok := parent[m]; !ok {
+				parent[m] = n
+				queue = append(queue, m)
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// FeedbackVertexSet returns a set of nodes whose removal makes the graph
+// acyclic, in a deterministic order. Like FeedbackArcSet, the set is only
+// approximately minimal, as finding the smallest set is NP-hard. It returns
+// nil for an acyclic graph. Nodes with a self-loop are always part of the set.
+//
+// The heuristic repeatedly splits the graph into strongly connected
+// components, and removes the node with the most edges within its component
+// from every component that has a cycle, until no cycles are left. A node at
+// the center of many cycles is removed first.
+func (g *Graph[Key]) FeedbackVertexSet() []Key {
+	w := g.Copy()
+
+	var set []Key
+	for {
+		_, id := w.condensation()
+
+		// The number of edges of every node within its component. Only
+		// components with a cycle have such edges.
+		degree := make(map[Key]int)
+		for from, e := range w.nodes {
+			for to := range e {
+				if id[from] == id[to] {
+					degree[from]++
+					degree[to]++
+				}
+			}
+		}
+
+		if len(degree) == 0 {
+			break
+		}
+
+		// The node with the highest degree of every component, preferring
+		// the smallest key.
+		best := make(map[int]Key)
+		for k, d := range degree {
+			c := id[k]
+			b, ok := best[c]
+			if !ok || d > degree[b] || d == degree[b] && compareKeys(k, b) < 0 {
+				best[c] = k
+			}
+		}
+
+		remove := make(map[Key]bool, len(best))
+		for _, k := range best {
+			remove[k] = true
+			set = append(set, k)
+		}
+		w.RemoveMatching(func(k Key) bool { return remove[k] })
+	}
+
+	sortKeys(set)
+
+	return set
+}
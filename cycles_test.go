@@ -0,0 +1,261 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestFeedbackArcSet(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with two cycles sharing the edge a -> b:
+	// a -> b -> c -> a and a -> b -> d -> a. Removing a -> b breaks both, so
+	// the heuristic should find a set of at most two edges.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "a")
+	g.Edge("b", "d")
+	g.Edge("d", "a")
+	g.Edge("a", "e")
+
+	arcs := g.FeedbackArcSet()
+
+	if len(arcs) == 0 || len(arcs) > 2 {
+		t.Errorf("expected 1 or 2 edges, got %v", arcs)
+		return
+	}
+
+	// Removing the edges must make the graph acyclic.
+	c := g.Copy()
+	for _, a := range arcs {
+		delete(c.nodes[a[0]], a[1])
+	}
+
+	if _, err := c.Sort(); err != nil {
+		t.Errorf("expected acyclic graph after removing %v, got %v", arcs, err)
+	}
+}
+
+func TestFeedbackArcSetDeterministic(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with a cycle of three nodes and a cycle of two
+	// nodes, connected by b -> x: a -> b -> c -> a and x -> y -> x. Every
+	// run must pick the same edges, in the same order.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "a")
+	g.Edge("x", "y")
+	g.Edge("y", "x")
+	g.Edge("b", "x")
+
+	expected := g.FeedbackArcSet()
+	if len(expected) != 2 {
+		t.Errorf("expected 2 edges, got %v", expected)
+	}
+
+	for i := 0; i < 100; i++ {
+		if arcs := g.FeedbackArcSet(); !reflect.DeepEqual(arcs, expected) {
+			t.Errorf("expected %v, got %v", expected, arcs)
+			return
+		}
+	}
+}
+
+func TestFeedbackArcSetAcyclic(t *testing.T) {
+	g := buildGraph(100)
+
+	if arcs := g.FeedbackArcSet(); len(arcs) != 0 {
+		t.Errorf("expected no edges, got %v", arcs)
+	}
+}
+
+func TestFeedbackArcSetSelfLoop(t *testing.T) {
+	g := New[string]()
+	g.Edge("a", "a")
+
+	arcs := g.FeedbackArcSet()
+
+	if len(arcs) != 1 || arcs[0] != [2]string{"a", "a"} {
+		t.Errorf("expected [[a a]], got %v", arcs)
+	}
+}
+
+func TestBackEdges(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> a,
+	// b -> d -> d and a -> e. Starting at a, the edges c -> a and d -> d
+	// point back to a node on the current path.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "a")
+	g.Edge("b", "d")
+	g.Edge("d", "d")
+	g.Edge("a", "e")
+
+	back := g.BackEdges()
+	expected := [][2]string{{"c", "a"}, {"d", "d"}}
+
+	if !reflect.DeepEqual(back, expected) {
+		t.Errorf("expected %v, got %v", expected, back)
+	}
+
+	for _, e := range back {
+		delete(g.nodes[e[0]], e[1])
+	}
+
+	if _, err := g.Sort(); err != nil {
+		t.Errorf("expected an acyclic graph, got %v", err)
+	}
+
+	if back := g.BackEdges(); back != nil {
+		t.Errorf("expected nil, got %v", back)
+	}
+}
+
+func TestCycleReport(t *testing.T) {
+	g := New[string]()
+
+	report, err := g.CycleReport()
+
+	if err != nil || report != nil {
+		t.Errorf("expected no report and no error, got %v and %v", report, err)
+	}
+
+	// We construct a graph with the following structure: a -> b -> c -> a,
+	// c -> d -> e, e -> e and f -> g -> f. The cycles are {a b c}, {e} and
+	// {f g}, while d is not part of any cycle.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "a")
+	g.Edge("c", "d")
+	g.Edge("d", "e")
+	g.Edge("e", "e")
+	g.Edge("f", "g")
+	g.Edge("g", "f")
+
+	report, err = g.CycleReport()
+
+	if !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+
+	expected := []CycleInfo[string]{
+		{Nodes: []string{"a", "b", "c"}, Edges: [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}}},
+		{Nodes: []string{"e"}, Edges: [][2]string{{"e", "e"}}},
+		{Nodes: []string{"f", "g"}, Edges: [][2]string{{"f", "g"}, {"g", "f"}}},
+	}
+
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("expected %v, got %v", expected, report)
+	}
+}
+
+func TestMutualEdges(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> a,
+	// c -> d -> c, b -> c, e -> e and x -> y -> z -> x. Only a and b, and c
+	// and d have edges to each other.
+	g.Edge("a", "b")
+	g.Edge("b", "a")
+	g.Edge("d", "c")
+	g.Edge("c", "d")
+	g.Edge("b", "c")
+	g.Edge("e", "e")
+	g.Edge("x", "y")
+	g.Edge("y", "z")
+	g.Edge("z", "x")
+
+	mutual := g.MutualEdges()
+	expected := [][2]string{{"a", "b"}, {"c", "d"}}
+
+	if !reflect.DeepEqual(mutual, expected) {
+		t.Errorf("expected %v, got %v", expected, mutual)
+	}
+
+	// The keys 1 and "1" are distinct, but compare as equal.
+	h := New[any]()
+	h.Edge(1, "1")
+	h.Edge("1", 1)
+
+	if mutual := h.MutualEdges(); len(mutual) != 1 {
+		t.Errorf("expected a single pair, got %v", mutual)
+	}
+}
+
+func TestShortestCycleThrough(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> d -> a,
+	// a -> e -> d, x -> x and a -> y. The shortest cycle through a is
+	// a -> e -> d -> a.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("d", "a")
+	g.Edge("a", "e")
+	g.Edge("e", "d")
+	g.Edge("x", "x")
+	g.Edge("a", "y")
+
+	if cycle, ok := g.ShortestCycleThrough("a"); !ok || !reflect.DeepEqual(cycle, []string{"a", "e", "d"}) {
+		t.Errorf("expected [a e d], got %v", cycle)
+	}
+
+	if cycle, ok := g.ShortestCycleThrough("c"); !ok || !reflect.DeepEqual(cycle, []string{"c", "d", "a", "b"}) {
+		t.Errorf("expected [c d a b], got %v", cycle)
+	}
+
+	if cycle, ok := g.ShortestCycleThrough("x"); !ok || !reflect.DeepEqual(cycle, []string{"x"}) {
+		t.Errorf("expected [x], got %v", cycle)
+	}
+
+	if _, ok := g.ShortestCycleThrough("y"); ok {
+		t.Error("expected no cycle through y")
+	}
+
+	if _, ok := g.ShortestCycleThrough("z"); ok {
+		t.Error("expected no cycle through z")
+	}
+}
+
+func TestFeedbackVertexSet(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with three cycles through x: x -> a -> x,
+	// x -> b -> x and x -> c -> d -> x, and a separate cycle e -> f -> e.
+	// Removing x breaks the first three cycles.
+	g.Edge("x", "a")
+	g.Edge("a", "x")
+	g.Edge("x", "b")
+	g.Edge("b", "x")
+	g.Edge("x", "c")
+	g.Edge("c", "d")
+	g.Edge("d", "x")
+	g.Edge("e", "f")
+	g.Edge("f", "e")
+
+	set := g.FeedbackVertexSet()
+	expected := []string{"e", "x"}
+
+	if !reflect.DeepEqual(set, expected) {
+		t.Errorf("expected %v, got %v", expected, set)
+	}
+
+	c := g.Copy()
+	c.RemoveMatching(func(k string) bool { return slices.Contains(set, k) })
+
+	if _, err := c.Sort(); err != nil {
+		t.Errorf("expected an acyclic graph, got %v", err)
+	}
+
+	if set := New[string]().FeedbackVertexSet(); set != nil {
+		t.Errorf("expected nil, got %v", set)
+	}
+}
@@ -0,0 +1,101 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import "slices"
+
+// DAG represents a directed acyclic graph. It is checked to be acyclic when it
+// is created and cannot be modified afterwards, so its methods never return
+// ErrCycle.
+type DAG[Key comparable] struct {
+	g *Graph[Key]
+
+	// order is a topological order of g, computed when the DAG is created.
+	order []Key
+}
+
+// AsDAG returns the graph as a DAG, or ErrCycle if the graph has a cycle. The
+// DAG has its own copy of the nodes and edges, so changing the graph
+// afterwards does not change the DAG.
+func (g *Graph[Key]) AsDAG() (*DAG[Key], error) {
+	c := g.Copy()
+
+	order, err := c.Sort()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DAG[Key]{g: c, order: order}, nil
+}
+
+// Graph returns a new, modifiable graph with the same nodes and edges.
+func (d *DAG[Key]) Graph() *Graph[Key] {
+	return d.g.Copy()
+}
+
+// Sort returns a topological sorted list of the nodes. See Graph.Sort.
+func (d *DAG[Key]) Sort() []Key {
+	return slices.Clone(d.order)
+}
+
+// Depths returns the depth of every node: the number of edges on the longest
+// path to it from a node without incoming edges, which has depth 0. Every
+// edge goes from a node to a node with a higher depth, so nodes with the same
+// depth cannot depend on each other.
+func (d *DAG[Key]) Depths() map[Key]int {
+	depths := make(map[Key]int, len(d.order))
+	for _, n := range d.order {
+		for m := range d.g.nodes[n] {
+			depths[m] = max(depths[m], depths[n]+1)
+		}
+		if _, ok := depths[n]; !ok {
+			depths[n] = 0
+		}
+	}
+	return depths
+}
+
+// LongestPath returns the nodes of a path with the most edges, which is the
+// critical path when every node takes the same time. If there are several
+// longest paths, it returns one of them, and it returns nil for an empty DAG.
+func (d *DAG[Key]) LongestPath() []Key {
+	if len(d.order) == 0 {
+		return nil
+	}
+
+	// The length of the longest path ending at every node, and the node
+	// before it on that path.
+	length := make(map[Key]int, len(d.order))
+	prev := make(map[Key]Key, len(d.order))
+
+	end := d.order[0]
+	for _, n := range d.order {
+		for m := range d.g.nodes[n] {
+			if length[n]+1 > length[m] {
+				length[m] = length[n] + 1
+				prev[m] = n
+			}
+		}
+		if length[n] > length[end] {
+			end = n
+		}
+	}
+
+	path := []Key{end}
+	for n, ok := prev[end]; ok; n, ok = prev[n] {
+		path = append(path, n)
+	}
+	slices.Reverse(path)
+
+	return path
+}
+
+// TransitiveReduction returns the transitive reduction of the DAG: the DAG
+// with the same nodes and reachability, and the fewest edges. See
+// Graph.ReductionEdges.
+func (d *DAG[Key]) TransitiveReduction() *DAG[Key] {
+	// The graph is acyclic, so MinimalEquivalent cannot fail.
+	m, _ := d.g.MinimalEquivalent()
+
+	return &DAG[Key]{g: m, order: slices.Clone(d.order)}
+}
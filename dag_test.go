@@ -0,0 +1,75 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAsDAG(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> d,
+	// a -> c and e -> d.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("a", "c")
+	g.Edge("e", "d")
+
+	d, err := g.AsDAG()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	checkOrder(t, g, d.Sort())
+
+	// Changing the graph does not change the DAG.
+	g.Edge("d", "a")
+
+	checkOrder(t, d.Graph(), d.Sort())
+
+	if _, err := g.AsDAG(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+
+	depths := d.Depths()
+	expected := map[string]int{"a": 0, "b": 1, "c": 2, "d": 3, "e": 0}
+
+	if !reflect.DeepEqual(depths, expected) {
+		t.Errorf("expected %v, got %v", expected, depths)
+	}
+
+	if path := d.LongestPath(); !reflect.DeepEqual(path, []string{"a", "b", "c", "d"}) {
+		t.Errorf("expected [a b c d], got %v", path)
+	}
+
+	r := d.TransitiveReduction()
+
+	if r.g.nodes["a"]["c"] || !r.g.nodes["a"]["b"] {
+		t.Errorf("expected a -> c to be removed, got %v", r.g.nodes)
+	}
+
+	checkOrder(t, r.Graph(), r.Sort())
+}
+
+func TestAsDAGEmpty(t *testing.T) {
+	d, err := New[string]().AsDAG()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if path := d.LongestPath(); path != nil {
+		t.Errorf("expected nil, got %v", path)
+	}
+
+	if depths := d.Depths(); len(depths) != 0 {
+		t.Errorf("expected no depths, got %v", depths)
+	}
+}
@@ -0,0 +1,122 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"maps"
+	"slices"
+)
+
+// OutDegrees returns the number of outgoing edges of every node. Nodes without
+// outgoing edges are included with a degree of 0.
+func (g *Graph[Key]) OutDegrees() map[Key]int {
+	degrees := make(map[Key]int, len(g.nodes))
+	for k, e := range g.nodes {
+		degrees[k] = len(e)
+	}
+	return degrees
+}
+
+// InDegrees returns the number of incoming edges of every node. Nodes without
+// incoming edges are included with a degree of 0. It scans all edges once, so
+// it is much cheaper than determining the in-degree of every node separately.
+// With the degree cache enabled, it copies the cached counts instead, see
+// EnableDegreeCache.
+func (g *Graph[Key]) InDegrees() map[Key]int {
+	if g.indegree != nil {
+		return maps.Clone(g.indegree)
+	}
+
+	degrees := make(map[Key]int, len(g.nodes))
+	for k, e := range g.nodes {
+		if _, ok := degrees[k]; !ok {
+			degrees[k] = 0
+		}
+		for to := range e {
+			degrees[to]++
+		}
+	}
+	return degrees
+}
+
+// Sinks returns the nodes without outgoing edges, in a deterministic order.
+// A node with only an edge to itself is not a sink.
+func (g *Graph[Key]) Sinks() []Key {
+	var sinks []Key
+	for k, e := range g.nodes {
+		if len(e) == 0 {
+			sinks = append(sinks, k)
+		}
+	}
+	sortKeys(sinks)
+	return sinks
+}
+
+// TopByInDegree returns the n nodes with the most incoming edges, with the
+// highest in-degree first. These are the nodes that most other nodes have an
+// edge to, so changing them affects the most nodes directly. Nodes with the
+// same in-degree are ordered by key, deterministically. It returns all nodes
+// if the graph has fewer than n nodes, and nil if n is not positive.
+func (g *Graph[Key]) TopByInDegree(n int) []Key {
+	if n <= 0 {
+		return nil
+	}
+
+	indegree := g.InDegrees()
+
+	keys := g.keys()
+	slices.SortStableFunc(keys, func(a, b Key) int {
+		return indegree[b] - indegree[a]
+	})
+
+	return keys[:min(n, len(keys))]
+}
+
+// OutDegreeViolations returns the nodes with more than limit outgoing edges,
+// in a deterministic order, for example to list the modules with too many
+// direct dependencies in a quality check. It returns nil if there are none.
+func (g *Graph[Key]) OutDegreeViolations(limit int) []Key {
+	var violations []Key
+	for k, e := range g.nodes {
+		if len(e) > limit {
+			violations = append(violations, k)
+		}
+	}
+	sortKeys(violations)
+	return violations
+}
+
+// IsSourceSink reports whether the graph is acyclic with exactly one node
+// without incoming edges and exactly one node without outgoing edges, such as
+// a pipeline with a single entry and a single exit, and returns these nodes.
+// Every node of such a graph can be reached from source and can reach sink.
+// In a graph with a single node, that node is both. It returns false for an
+// empty graph.
+func (g *Graph[Key]) IsSourceSink() (source, sink Key, ok bool) {
+	var zero Key
+
+	if !g.kahn(func(Key) bool { return true }) {
+		return zero, zero, false
+	}
+
+	sinks := g.Sinks()
+	if len(sinks) != 1 {
+		return zero, zero, false
+	}
+
+	found := false
+	for k, d := range g.InDegrees() {
+		if d != 0 {
+			continue
+		}
+		if found {
+			return zero, zero, false
+		}
+		source, found = k, true
+	}
+	if !found {
+		return zero, zero, false
+	}
+
+	return source, sinks[0], true
+}
@@ -0,0 +1,136 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDegrees(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, a -> c,
+	// b -> c and an isolated node d.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "c")
+	g.Node("d")
+
+	out := g.OutDegrees()
+	if !reflect.DeepEqual(out, map[string]int{"a": 2, "b": 1, "c": 0, "d": 0}) {
+		t.Errorf("expected map[a:2 b:1 c:0 d:0], got %v", out)
+	}
+
+	in := g.InDegrees()
+	if !reflect.DeepEqual(in, map[string]int{"a": 0, "b": 1, "c": 2, "d": 0}) {
+		t.Errorf("expected map[a:0 b:1 c:2 d:0], got %v", in)
+	}
+}
+
+func TestSinks(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, a -> c,
+	// c -> c and an isolated node d. The self-loop means c is not a sink.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("c", "c")
+	g.Node("d")
+
+	if sinks := g.Sinks(); !reflect.DeepEqual(sinks, []string{"b", "d"}) {
+		t.Errorf("expected [b d], got %v", sinks)
+	}
+}
+
+func TestTopByInDegree(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> c, b -> c,
+	// a -> d, b -> d, x -> y and a -> y. The nodes c, d and y all have an
+	// in-degree of 2, so they are ordered by key.
+	g.Edge("a", "c")
+	g.Edge("b", "c")
+	g.Edge("a", "d")
+	g.Edge("b", "d")
+	g.Edge("x", "y")
+	g.Edge("a", "y")
+
+	if keys := g.TopByInDegree(2); !reflect.DeepEqual(keys, []string{"c", "d"}) {
+		t.Errorf("expected [c d], got %v", keys)
+	}
+
+	if keys := g.TopByInDegree(10); !reflect.DeepEqual(keys, []string{"c", "d", "y", "a", "b", "x"}) {
+		t.Errorf("expected [c d y a b x], got %v", keys)
+	}
+
+	if keys := g.TopByInDegree(0); keys != nil {
+		t.Errorf("expected nil, got %v", keys)
+	}
+}
+
+func TestOutDegreeViolations(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, a -> c,
+	// a -> d, b -> c, b -> d and c -> d.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("a", "d")
+	g.Edge("b", "c")
+	g.Edge("b", "d")
+	g.Edge("c", "d")
+
+	if keys := g.OutDegreeViolations(1); !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		t.Errorf("expected [a b], got %v", keys)
+	}
+
+	if keys := g.OutDegreeViolations(3); keys != nil {
+		t.Errorf("expected nil, got %v", keys)
+	}
+}
+
+func TestIsSourceSink(t *testing.T) {
+	g := New[string]()
+
+	if _, _, ok := g.IsSourceSink(); ok {
+		t.Errorf("expected an empty graph not to match")
+	}
+
+	// We construct a graph with the following structure: a -> b -> d and
+	// a -> c -> d.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "d")
+	g.Edge("c", "d")
+
+	if source, sink, ok := g.IsSourceSink(); !ok || source != "a" || sink != "d" {
+		t.Errorf("expected a and d, got %v, %v and %v", source, sink, ok)
+	}
+
+	// A second sink.
+	g.Edge("c", "e")
+
+	if _, _, ok := g.IsSourceSink(); ok {
+		t.Errorf("expected two sinks not to match")
+	}
+
+	// A second source.
+	g.Edge("e", "d")
+	g.Edge("x", "b")
+
+	if _, _, ok := g.IsSourceSink(); ok {
+		t.Errorf("expected two sources not to match")
+	}
+
+	// A cycle.
+	h := New[string]()
+	h.Edge("a", "b")
+	h.Edge("b", "c")
+	h.Edge("c", "b")
+	h.Edge("b", "d")
+
+	if _, _, ok := h.IsSourceSink(); ok {
+		t.Errorf("expected a cyclic graph not to match")
+	}
+}
@@ -0,0 +1,161 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+// Dominators returns the immediate dominator of every node that can be
+// reached from entry. A node d dominates a node n if every path from entry to
+// n goes through d, so d definitely comes before n. The immediate dominator of
+// n is the dominator of n that is closest to it, other than n itself, and
+// every other dominator of n also dominates it, so the result describes a tree
+// with entry at the root. The immediate dominator of entry is entry itself.
+// Nodes that cannot be reached from entry are not included. It returns nil if
+// entry does not exist.
+//
+// Dominators uses the iterative algorithm of Cooper, Harvey and Kennedy,
+// which processes the nodes in reverse postorder until the dominators no
+// longer change. It is simple and fast in practice, and the number of
+// iterations is small for most graphs.
+func (g *Graph[Key]) Dominators(entry Key) map[Key]Key {
+	// https://en.wikipedia.org/wiki/Dominator_(graph_theory)#Algorithms
+
+	entry = g.key(entry)
+	if _, ok := g.nodes[entry]; !ok {
+		return nil
+	}
+
+	// The number of every node in the postorder of a depth-first search from
+	// entry, and the nodes in that order.
+	number := make(map[Key]int, len(g.nodes))
+	var postorder []Key
+
+	visited := make(map[Key]bool, len(g.nodes))
+	var visit func(n Key)
+	visit = func(n Key) {
+		visited[n] = true
+		for m := range g.nodes[n] {
+			if !visited[m] {
+				visit(m)
+			}
+		}
+		number[n] = len(postorder)
+		postorder = append(postorder, n)
+	}
+	visit(entry)
+
+	r := g.Reverse()
+
+	idom := map[Key]Key{entry: entry}
+
+	// intersect returns the closest common dominator of two nodes, by walking
+	// up the dominator tree from the node with the lowest number, which is
+	// the furthest from entry, until both meet.
+	intersect := func(a, b Key) Key {
+		for a != b {
+			for number[a] < number[b] {
+				a = idom[a]
+			}
+			for number[b] < number[a] {
+				b = idom[b]
+			}
+		}
+		return a
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		// We visit the nodes in reverse postorder, so most predecessors of
+		// a node are processed before it. Entry is the last node of the
+		// postorder.
+		for i := len(postorder) - 2; i >= 0; i-- {
+			n := postorder[i]
+
+			var dom Key
+			found := false
+			for p := range r.nodes[n] {
+				if _, ok := idom[p]; !ok {
+					continue
+				}
+				if !found {
+					dom, found = p, true
+				} else {
+					dom = intersect(p, dom)
+				}
+			}
+
+			if d, ok := idom[n]; !ok || d != dom {
+				idom[n] = dom
+				changed = true
+			}
+		}
+	}
+
+	return idom
+}
+
+// PostDominators returns the immediate post-dominator of every node from which
+// exit can be reached. A node d post-dominates a node n if every path from n to
+// exit goes through d, so d definitely comes after n. These are the
+// dominators of the reversed graph with exit as entry, see Dominators. The
+// immediate post-dominator of exit is exit itself. It returns nil if exit does
+// not exist.
+func (g *Graph[Key]) PostDominators(exit Key) map[Key]Key {
+	return g.Reverse().Dominators(exit)
+}
+
+// DominanceFrontier returns the dominance frontier of every node that can be
+// reached from entry, in a deterministic order: the nodes where the dominance
+// of the node stops. A node f is in the frontier of n if n dominates a node
+// with an edge to f, but does not strictly dominate f itself, so f is the
+// first node where a path from entry that avoids n joins. This is where phi
+// functions are placed in the construction of SSA form. Nodes with an empty
+// frontier are not part of the result. It returns nil if entry does not exist.
+//
+// The frontiers are computed from the immediate dominators of Dominators,
+// with the algorithm of Cooper, Harvey and Kennedy: for every node, it walks up
+// the dominator tree from every node with an edge to it, until it reaches its
+// immediate dominator.
+func (g *Graph[Key]) DominanceFrontier(entry Key) map[Key][]Key {
+	entry = g.key(entry)
+	idom := g.Dominators(entry)
+	if idom == nil {
+		return nil
+	}
+
+	r := g.Reverse()
+
+	sets := make(map[Key]map[Key]bool)
+	for n := range idom {
+		for p := range r.nodes[n] {
+			if _, ok := idom[p]; !ok {
+				continue
+			}
+
+			// Every node from p up to the immediate dominator of n dominates
+			// p, but not n. Entry does not strictly dominate itself, so for
+			// entry the walk includes entry.
+			for runner := p; ; runner = idom[runner] {
+				if runner == idom[n] && n != entry {
+					break
+				}
+				if sets[runner] == nil {
+					sets[runner] = make(map[Key]bool)
+				}
+				sets[runner][n] = true
+				if runner == entry {
+					break
+				}
+			}
+		}
+	}
+
+	frontier := make(map[Key][]Key, len(sets))
+	for n, set := range sets {
+		for k := range set {
+			frontier[n] = append(frontier[n], k)
+		}
+		sortKeys(frontier[n])
+	}
+
+	return frontier
+}
@@ -0,0 +1,100 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDominators(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: e -> a -> c,
+	// e -> b -> c, c -> d -> c, d -> x and an unreachable node u -> d. Both
+	// a and b lead to c, so only e dominates c, and c dominates d.
+	g.Edge("e", "a")
+	g.Edge("e", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("d", "c")
+	g.Edge("d", "x")
+	g.Edge("u", "d")
+
+	expected := map[string]string{
+		"e": "e",
+		"a": "e",
+		"b": "e",
+		"c": "e",
+		"d": "c",
+		"x": "d",
+	}
+
+	if idom := g.Dominators("e"); !reflect.DeepEqual(idom, expected) {
+		t.Errorf("expected %v, got %v", expected, idom)
+	}
+
+	if idom := g.Dominators("z"); idom != nil {
+		t.Errorf("expected nil, got %v", idom)
+	}
+}
+
+func TestPostDominators(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: s -> a -> c -> x,
+	// s -> b -> c and b -> x. From b, x can be reached without c, so only x
+	// post-dominates b and s.
+	g.Edge("s", "a")
+	g.Edge("s", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "c")
+	g.Edge("c", "x")
+	g.Edge("b", "x")
+
+	expected := map[string]string{
+		"x": "x",
+		"c": "x",
+		"a": "c",
+		"b": "x",
+		"s": "x",
+	}
+
+	if ipdom := g.PostDominators("x"); !reflect.DeepEqual(ipdom, expected) {
+		t.Errorf("expected %v, got %v", expected, ipdom)
+	}
+}
+
+func TestDominanceFrontier(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: e -> a -> j,
+	// e -> b -> j, j -> l -> j, l -> x and x -> e. The paths through a and b
+	// join at j, the loop of l returns to j, and x returns to e.
+	g.Edge("e", "a")
+	g.Edge("e", "b")
+	g.Edge("a", "j")
+	g.Edge("b", "j")
+	g.Edge("j", "l")
+	g.Edge("l", "j")
+	g.Edge("l", "x")
+	g.Edge("x", "e")
+
+	expected := map[string][]string{
+		"e": {"e"},
+		"a": {"j"},
+		"b": {"j"},
+		"j": {"e", "j"},
+		"l": {"e", "j"},
+		"x": {"e"},
+	}
+
+	if frontier := g.DominanceFrontier("e"); !reflect.DeepEqual(frontier, expected) {
+		t.Errorf("expected %v, got %v", expected, frontier)
+	}
+
+	if frontier := g.DominanceFrontier("z"); frontier != nil {
+		t.Errorf("expected nil, got %v", frontier)
+	}
+}
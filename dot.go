@@ -0,0 +1,350 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// ParseDOT reads a directed graph in the Graphviz DOT language. It supports a
+// subset of the language:
+//
+//   - a single digraph, optionally strict and optionally named;
+//   - node statements (a), which add a node;
+//   - edge statements (a -> b -> c), which add the nodes and edges;
+//   - attribute lists ([color=red]) on nodes and edges, which are ignored;
+//   - attribute statements (node [shape=box] or rankdir=LR), which are
+//     ignored;
+//   - bare (a_1, 2.5) and double-quoted ("a b") IDs;
+//   - comments (// ..., # ... and /* ... */).
+//
+// Undirected graphs, subgraphs, ports and HTML IDs are not supported and
+// result in an error.
+func ParseDOT(r io.Reader) (*Graph[string], error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := dotTokens(string(src))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &dotParser{tokens: tokens}
+	g := New[string]()
+
+	if p.keyword("strict") {
+		p.next()
+	}
+	if !p.keyword("digraph") {
+		return nil, p.unexpected()
+	}
+	p.next()
+	if p.peek().kind == dotID {
+		p.next()
+	}
+	if p.peek().kind != '{' {
+		return nil, p.unexpected()
+	}
+	p.next()
+
+	for p.peek().kind != '}' {
+		t := p.peek()
+		switch {
+		case t.kind == ';':
+			p.next()
+
+		case t.kind != dotID:
+			return nil, p.unexpected()
+
+		case p.keyword("graph", "node", "edge") && p.peekAt(1).kind == '[':
+			// An attribute statement, which we ignore.
+			p.next()
+			if err := p.skipAttributes(); err != nil {
+				return nil, err
+			}
+
+		case p.peekAt(1).kind == '=':
+			// A graph attribute, which we ignore.
+			p.next()
+			p.next()
+			if p.peek().kind != dotID {
+				return nil, p.unexpected()
+			}
+			p.next()
+
+		default:
+			// A node or edge statement.
+			from := p.next().value
+			g.Node(from)
+
+			for p.peek().kind == dotArrow {
+				p.next()
+				if p.peek().kind != dotID {
+					return nil, p.unexpected()
+				}
+				to := p.next().value
+				g.Edge(from, to)
+				from = to
+			}
+
+			if err := p.skipAttributes(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	p.next()
+
+	if p.peek().kind != dotEOF {
+		return nil, p.unexpected()
+	}
+
+	return g, nil
+}
+
+// The kinds of DOT tokens that are not a single character.
+const (
+	dotEOF = -1 - iota
+	dotID
+	dotArrow
+)
+
+// dotToken is a token of the DOT language. Its kind is one of the dot
+// constants, or the character for single character tokens.
+type dotToken struct {
+	kind  int
+	value string
+	line  int
+}
+
+// dotTokens splits DOT source into tokens. The last token is always a dotEOF
+// token.
+func dotTokens(src string) ([]dotToken, error) {
+	var tokens []dotToken
+	line := 1
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+
+		case unicode.IsSpace(rune(c)):
+			i++
+
+		case strings.HasPrefix(src[i:], "//") || c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("dot: line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+
+		case strings.HasPrefix(src[i:], "->"):
+			tokens = append(tokens, dotToken{kind: dotArrow, value: "->", line: line})
+			i += 2
+
+		case strings.ContainsRune("{}[];=,", rune(c)):
+			tokens = append(tokens, dotToken{kind: int(c), value: string(c), line: line})
+			i++
+
+		case c == '"':
+			// A quoted ID. The only escape sequence DOT defines is \", any
+			// other backslash is kept as is. Like Graphviz, we keep \\ as is
+			// too, but read it as a unit, so a quote after it ends the ID.
+			start := line
+			var b strings.Builder
+			i++
+			for ; i < len(src) && src[i] != '"'; i++ {
+				if src[i] == '\\' && i+1 < len(src) {
+					switch src[i+1] {
+					case '"':
+						i++
+					case '\\':
+						b.WriteByte(src[i])
+						i++
+					}
+				}
+				if src[i] == '\n' {
+					line++
+				}
+				b.WriteByte(src[i])
+			}
+			if i == len(src) {
+				return nil, fmt.Errorf("dot: line %d: unterminated string", start)
+			}
+			i++
+			tokens = append(tokens, dotToken{kind: dotID, value: b.String(), line: start})
+
+		case dotIDByte(c) || c == '-' && i+1 < len(src) && (src[i+1] == '.' || unicode.IsDigit(rune(src[i+1]))):
+			// A bare ID, which is either an identifier or a number. Numbers
+			// can be negative.
+			j := i + 1
+			for j < len(src) && dotIDByte(src[j]) {
+				j++
+			}
+			tokens = append(tokens, dotToken{kind: dotID, value: src[i:j], line: line})
+			i = j
+
+		default:
+			return nil, fmt.Errorf("dot: line %d: unexpected %q", line, c)
+		}
+	}
+
+	return append(tokens, dotToken{kind: dotEOF, line: line}), nil
+}
+
+// dotIDByte reports whether c can be part of a bare ID.
+func dotIDByte(c byte) bool {
+	return c == '_' || c == '.' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+// dotParser keeps track of the position in a list of DOT tokens.
+type dotParser struct {
+	tokens []dotToken
+	pos    int
+}
+
+// peek returns the current token without consuming it.
+func (p *dotParser) peek() dotToken {
+	return p.peekAt(0)
+}
+
+// peekAt returns the token n positions after the current token without
+// consuming anything.
+func (p *dotParser) peekAt(n int) dotToken {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+// next consumes and returns the current token.
+func (p *dotParser) next() dotToken {
+	t := p.peek()
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+	return t
+}
+
+// keyword reports whether the current token is one of the given keywords.
+// Keywords are case-insensitive in DOT.
+func (p *dotParser) keyword(keywords ...string) bool {
+	t := p.peek()
+	if t.kind != dotID {
+		return false
+	}
+	for _, k := range keywords {
+		if strings.EqualFold(t.value, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipAttributes consumes any number of attribute lists.
+func (p *dotParser) skipAttributes() error {
+	for p.peek().kind == '[' {
+		p.next()
+		for p.peek().kind != ']' {
+			switch p.peek().kind {
+			case dotID, '=', ',', ';':
+				p.next()
+			default:
+				return p.unexpected()
+			}
+		}
+		p.next()
+	}
+	return nil
+}
+
+// unexpected returns an error for the current token.
+func (p *dotParser) unexpected() error {
+	t := p.peek()
+	if t.kind == dotEOF {
+		return fmt.Errorf("dot: line %d: unexpected end of input", t.line)
+	}
+	return fmt.Errorf("dot: line %d: unexpected %q", t.line, t.value)
+}
+
+// CondensationDOT writes the strongly connected components of the graph in
+// the Graphviz DOT language. Every component is drawn as a cluster with its
+// nodes, and there is a single edge between two clusters if any node of one
+// has an edge to a node of the other. The edges within the components are not
+// drawn, so the clusters and edges form the acyclic graph of components.
+// Clusters are written in topological order, and nodes and edges in a
+// deterministic order. Nodes are identified by their keys formatted with
+// fmt's %v verb.
+//
+// The edges between clusters are drawn between the first nodes of the
+// clusters, and clipped at the cluster borders with the lhead and ltail
+// attributes of Graphviz.
+func (g *Graph[Key]) CondensationDOT(w io.Writer) error {
+	components, _ := g.SortComponents()
+
+	id := make(map[Key]int, len(g.nodes))
+	for i, c := range components {
+		for _, k := range c {
+			id[k] = i
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph {\n\tcompound=true;\n")
+
+	for i, c := range components {
+		fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n", i)
+		for _, k := range c {
+			fmt.Fprintf(&b, "\t\t%s;\n", dotQuote(fmt.Sprint(k)))
+		}
+		b.WriteString("\t}\n")
+	}
+
+	// The edges between components, once for every pair, in the order of the
+	// components.
+	edges := make(map[[2]int]bool)
+	for from, e := range g.nodes {
+		for to := range e {
+			if f, t := id[from], id[to]; f != t {
+				edges[[2]int{f, t}] = true
+			}
+		}
+	}
+
+	pairs := make([][2]int, 0, len(edges))
+	for e := range edges {
+		pairs = append(pairs, e)
+	}
+	sortEdges(pairs)
+
+	for _, e := range pairs {
+		fmt.Fprintf(&b, "\t%s -> %s [ltail=cluster_%d, lhead=cluster_%d];\n",
+			dotQuote(fmt.Sprint(components[e[0]][0])), dotQuote(fmt.Sprint(components[e[1]][0])), e[0], e[1])
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote returns s as a double-quoted DOT ID. Backslashes are escaped too,
+// so a backslash at the end of s does not escape the closing quote.
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+// dotEscaper escapes backslashes and double quotes for dotQuote.
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
@@ -0,0 +1,127 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDOT(t *testing.T) {
+	src := `
+		// A small dependency graph.
+		strict digraph deps {
+			rankdir=LR;
+			node [shape=box];
+
+			a -> b -> c [color=red, style="dashed"];
+			"d e" -> c
+			f # an isolated node
+			/* a multi-line
+			   comment */
+			-1.5 -> a;
+		}
+	`
+
+	g, err := ParseDOT(strings.NewReader(src))
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := map[string]Edges[string]{
+		"a":    {"b": true},
+		"b":    {"c": true},
+		"c":    {},
+		"d e":  {"c": true},
+		"f":    {},
+		"-1.5": {"a": true},
+	}
+
+	if !reflect.DeepEqual(g.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, g.nodes)
+	}
+}
+
+func TestParseDOTBackslashes(t *testing.T) {
+	// A backslash before a backslash is kept as is, and does not escape the
+	// quote after it.
+	g, err := ParseDOT(strings.NewReader(`digraph { "a\\" -> b; "c\\\"" -> b }`))
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := map[string]Edges[string]{
+		`a\\`:  {"b": true},
+		`c\\"`: {"b": true},
+		"b":    {},
+	}
+
+	if !reflect.DeepEqual(g.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, g.nodes)
+	}
+}
+
+func TestParseDOTErrors(t *testing.T) {
+	for _, src := range []string{
+		`graph { a -- b }`,
+		`digraph { a -> }`,
+		`digraph { subgraph x { a } }`,
+		`digraph { a -> b`,
+		`digraph { "a }`,
+		`digraph { a } b`,
+	} {
+		if _, err := ParseDOT(strings.NewReader(src)); err == nil {
+			t.Errorf("expected error for %q", src)
+		}
+	}
+}
+
+func TestCondensationDOT(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> a,
+	// a -> c, b -> c, c -> "d" and "d" -> C:\. The edges from a and b to c
+	// collapse into a single edge between their clusters.
+	g.Edge("a", "b")
+	g.Edge("b", "a")
+	g.Edge("a", "c")
+	g.Edge("b", "c")
+	g.Edge("c", `"d"`)
+	g.Edge(`"d"`, `C:\`)
+
+	var b strings.Builder
+	if err := g.CondensationDOT(&b); err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := `digraph {
+	compound=true;
+	subgraph cluster_0 {
+		"a";
+		"b";
+	}
+	subgraph cluster_1 {
+		"c";
+	}
+	subgraph cluster_2 {
+		"\"d\"";
+	}
+	subgraph cluster_3 {
+		"C:\\";
+	}
+	"a" -> "c" [ltail=cluster_0, lhead=cluster_1];
+	"c" -> "\"d\"" [ltail=cluster_1, lhead=cluster_2];
+	"\"d\"" -> "C:\\" [ltail=cluster_2, lhead=cluster_3];
+}
+`
+
+	if b.String() != expected {
+		t.Errorf("expected %v, got %v", expected, b.String())
+	}
+}
@@ -0,0 +1,129 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+// EdgeGraph represents a directed graph that stores a value of type E for
+// every edge, for example the reason an edge was added. It embeds a Graph, so
+// all methods of Graph can be used on it. Edges added with the methods of
+// Graph, such as Edge and Add, have no value.
+type EdgeGraph[Key comparable, E any] struct {
+	*Graph[Key]
+
+	// data are the values of the edges, by their endpoints.
+	data map[[2]Key]E
+}
+
+// NewEdgeGraph returns a new graph with values on its edges.
+func NewEdgeGraph[Key comparable, E any]() *EdgeGraph[Key, E] {
+	return &EdgeGraph[Key, E]{
+		Graph: New[Key](),
+		data:  make(map[[2]Key]E),
+	}
+}
+
+// SetEdge adds an edge to the graph with a value, replacing the value if the
+// edge already has one. It creates the nodes if they do not exist.
+func (eg *EdgeGraph[Key, E]) SetEdge(from, to Key, data E) {
+	from, to = eg.key(from), eg.key(to)
+
+	eg.node(from)
+	eg.node(to)
+	eg.link(from, to)
+	eg.data[[2]Key{from, to}] = data
+}
+
+// EdgeData returns the value of an edge. It returns false if the edge does not
+// exist or has no value.
+func (eg *EdgeGraph[Key, E]) EdgeData(from, to Key) (E, bool) {
+	from, to = eg.key(from), eg.key(to)
+
+	if !eg.nodes[from][to] {
+		var zero E
+		return zero, false
+	}
+
+	data, ok := eg.data[[2]Key{from, to}]
+	return data, ok
+}
+
+// Copy returns a new graph with the same nodes, edges and values, like
+// Graph.Copy. The values themselves are copied by assignment.
+func (eg *EdgeGraph[Key, E]) Copy() *EdgeGraph[Key, E] {
+	c := &EdgeGraph[Key, E]{
+		Graph: eg.Graph.Copy(),
+		data:  make(map[[2]Key]E, len(eg.data)),
+	}
+
+	for e, data := range eg.data {
+		if eg.nodes[e[0]][e[1]] {
+			c.data[e] = data
+		}
+	}
+
+	return c
+}
+
+// RemoveMatching is like Graph.RemoveMatching, and also removes the values of
+// the removed edges.
+func (eg *EdgeGraph[Key, E]) RemoveMatching(pred func(Key) bool) int {
+	n := eg.Graph.RemoveMatching(pred)
+	if n > 0 {
+		eg.prune()
+	}
+	return n
+}
+
+// RemoveEdgeOrdered is like Graph.RemoveEdgeOrdered, and also removes the
+// value of the edge.
+func (eg *EdgeGraph[Key, E]) RemoveEdgeOrdered(order []Key, from, to Key) []Key {
+	order = eg.Graph.RemoveEdgeOrdered(order, from, to)
+	delete(eg.data, [2]Key{eg.key(from), eg.key(to)})
+	return order
+}
+
+// Rename is like Graph.Rename, and also keeps the values of the edges from and
+// to the node.
+func (eg *EdgeGraph[Key, E]) Rename(old, new Key) error {
+	if err := eg.Graph.Rename(old, new); err != nil {
+		return err
+	}
+
+	old, new = eg.key(old), eg.key(new)
+	eg.rekey(func(k Key) Key {
+		if k == old {
+			return new
+		}
+		return k
+	})
+
+	return nil
+}
+
+// SetKeyNormalizer is like Graph.SetKeyNormalizer, and also normalizes the
+// endpoints of the values. When edges are merged, the value of one of them is
+// kept, which one is not specified.
+func (eg *EdgeGraph[Key, E]) SetKeyNormalizer(fn func(Key) Key) {
+	eg.Graph.SetKeyNormalizer(fn)
+	if fn != nil {
+		eg.rekey(fn)
+	}
+}
+
+// prune removes the values of edges that no longer exist.
+func (eg *EdgeGraph[Key, E]) prune() {
+	for e := range eg.data {
+		if !eg.nodes[e[0]][e[1]] {
+			delete(eg.data, e)
+		}
+	}
+}
+
+// rekey changes the endpoints of all values with fn.
+func (eg *EdgeGraph[Key, E]) rekey(fn func(Key) Key) {
+	data := eg.data
+	eg.data = make(map[[2]Key]E, len(data))
+
+	for e, d := range data {
+		eg.data[[2]Key{fn(e[0]), fn(e[1])}] = d
+	}
+}
@@ -0,0 +1,102 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEdgeGraph(t *testing.T) {
+	g := NewEdgeGraph[string, string]()
+
+	// We construct a graph with the following structure: a -> b -> c, where
+	// a -> b has a value and b -> c does not.
+	g.SetEdge("a", "b", "v1")
+	g.Edge("b", "c")
+
+	if data, ok := g.EdgeData("a", "b"); !ok || data != "v1" {
+		t.Errorf("expected v1, got %v, %v", data, ok)
+	}
+
+	if _, ok := g.EdgeData("b", "c"); ok {
+		t.Error("expected no value for b -> c")
+	}
+
+	g.SetEdge("a", "b", "v2")
+
+	if data, _ := g.EdgeData("a", "b"); data != "v2" {
+		t.Errorf("expected v2, got %v", data)
+	}
+
+	// The methods of Graph work on the embedded graph.
+	keys, err := g.Sort()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("expected [a b c], got %v", keys)
+	}
+
+	// The copy has its own values.
+	c := g.Copy()
+	c.SetEdge("a", "b", "v3")
+
+	if data, _ := g.EdgeData("a", "b"); data != "v2" {
+		t.Errorf("expected v2 in the graph, got %v", data)
+	}
+
+	if data, _ := c.EdgeData("a", "b"); data != "v3" {
+		t.Errorf("expected v3 in the copy, got %v", data)
+	}
+}
+
+func TestEdgeGraphChanges(t *testing.T) {
+	g := NewEdgeGraph[string, int]()
+
+	// We construct a graph with the following structure: a -> b -> c, with
+	// values on both edges.
+	g.SetEdge("a", "b", 1)
+	g.SetEdge("b", "c", 2)
+
+	if err := g.Rename("b", "x"); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if data, ok := g.EdgeData("a", "x"); !ok || data != 1 {
+		t.Errorf("expected 1, got %v, %v", data, ok)
+	}
+
+	if data, ok := g.EdgeData("x", "c"); !ok || data != 2 {
+		t.Errorf("expected 2, got %v, %v", data, ok)
+	}
+
+	// Removing a node removes the values of its edges, so they do not return
+	// when the edge is added again.
+	g.RemoveMatching(func(k string) bool { return k == "c" })
+	g.Edge("x", "c")
+
+	if _, ok := g.EdgeData("x", "c"); ok {
+		t.Error("expected no value for x -> c")
+	}
+
+	// Removing an edge removes its value too.
+	g.SetEdge("a", "c", 3)
+	g.RemoveEdgeOrdered([]string{"a", "x", "c"}, "a", "c")
+	g.Edge("a", "c")
+
+	if _, ok := g.EdgeData("a", "c"); ok {
+		t.Error("expected no value for a -> c")
+	}
+
+	g.SetKeyNormalizer(strings.ToUpper)
+
+	if data, ok := g.EdgeData("a", "x"); !ok || data != 1 {
+		t.Errorf("expected 1, got %v, %v", data, ok)
+	}
+}
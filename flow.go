@@ -0,0 +1,140 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+// MinCut returns a smallest set of edges whose removal leaves no path from
+// one node to another, in a deterministic order. It returns nil if there is no
+// path to begin with, if the nodes are the same, or if one of them does not
+// exist.
+//
+// MinCut computes a maximum flow from from to to, where every edge has a
+// capacity of one, with the Edmonds-Karp algorithm: it repeatedly finds a
+// shortest path with capacity left using a breadth-first search. The flow is
+// at most the out-degree of from, so this takes O(d * (n + m)) time for
+// d = [out-degree of from], n = [number of nodes] and m = [number of edges].
+// The edges from the nodes that can still be reached afterwards to the other
+// nodes form the cut.
+func (g *Graph[Key]) MinCut(from, to Key) [][2]Key {
+	from, to = g.key(from), g.key(to)
+	if from == to {
+		return nil
+	}
+	if _, ok := g.nodes[from]; !ok {
+		return nil
+	}
+	if _, ok := g.nodes[to]; !ok {
+		return nil
+	}
+
+	flow, source := g.maxFlow(from, to)
+	if flow == 0 {
+		return nil
+	}
+
+	var cut [][2]Key
+	for n := range source {
+		for m := range g.nodes[n] {
+			if !source[m] {
+				cut = append(cut, [2]Key{n, m})
+			}
+		}
+	}
+	sortEdges(cut)
+
+	return cut
+}
+
+// EdgeConnectivity returns the largest number of paths from one node to
+// another that do not share any edge. By Menger's theorem, this is the number
+// of edges in MinCut, so it tells how many edges must fail before to can no
+// longer be reached from from. It returns 0 if there is no path, if the nodes
+// are the same, or if one of them does not exist. Like MinCut, it takes
+// O(d * (n + m)) time.
+func (g *Graph[Key]) EdgeConnectivity(from, to Key) int {
+	from, to = g.key(from), g.key(to)
+	if from == to {
+		return 0
+	}
+	if _, ok := g.nodes[from]; !ok {
+		return 0
+	}
+	if _, ok := g.nodes[to]; !ok {
+		return 0
+	}
+
+	flow, _ := g.maxFlow(from, to)
+
+	return flow
+}
+
+// maxFlow returns the maximum flow from s to t, where every edge has a
+// capacity of one, and the nodes that can be reached from s through edges with
+// capacity left once the flow is maximal. The nodes must exist and differ.
+func (g *Graph[Key]) maxFlow(s, t Key) (int, map[Key]bool) {
+	// https://en.wikipedia.org/wiki/Edmonds%E2%80%93Karp_algorithm
+
+	// The flow over every edge, which is 0 or 1. The residual graph has an
+	// edge a -> b with capacity left if a -> b has no flow, or if b -> a has
+	// flow that can be sent back.
+	flow := make(map[[2]Key]bool)
+	r := g.Reverse()
+
+	// augment searches a shortest path from s to t in the residual graph. If
+	// it finds one, it sends one unit of flow over it. It returns the nodes it
+	// visited.
+	augment := func() (bool, map[Key]bool) {
+		parent := map[Key]Key{s: s}
+		queue := []Key{s}
+
+		visit := func(n, m Key) {
+			if _, ok := parent[m]; !ok {
+				parent[m] = n
+				queue = append(queue, m)
+			}
+		}
+
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+
+			if n == t {
+				for m := t; m != s; m = parent[m] {
+					p := parent[m]
+					if flow[[2]Key{m, p}] {
+						// We cancel flow that went the other way.
+						delete(flow, [2]Key{m, p})
+					} else {
+						flow[[2]Key{p, m}] = true
+					}
+				}
+				return true, nil
+			}
+
+			for m := range g.nodes[n] {
+				if !flow[[2]Key{n, m}] {
+					visit(n, m)
+				}
+			}
+			for m := range r.nodes[n] {
+				if flow[[2]Key{m, n}] {
+					visit(n, m)
+				}
+			}
+		}
+
+		visited := make(map[Key]bool, len(parent))
+		for k := range parent {
+			visited[k] = true
+		}
+		return false, visited
+	}
+
+	total := 0
+	for {
+		ok, source := augment()
+		if !ok {
+			return total, source
+		}
+		total++
+	}
+}
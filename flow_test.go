@@ -0,0 +1,105 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMinCut(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: s -> a -> t,
+	// s -> b -> c -> t, s -> d -> c and a -> c. All paths to t go through
+	// a -> t or c -> t, so the smallest cut has two edges.
+	g.Edge("s", "a")
+	g.Edge("a", "t")
+	g.Edge("s", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "t")
+	g.Edge("s", "d")
+	g.Edge("d", "c")
+	g.Edge("a", "c")
+
+	cut := g.MinCut("s", "t")
+
+	if len(cut) != 2 {
+		t.Errorf("expected 2 edges, got %v", cut)
+	}
+
+	// Removing the cut must leave no path from s to t.
+	c := g.Copy()
+	for _, e := range cut {
+		c.unlink(e[0], e[1])
+	}
+
+	if c.ReachableAny([]string{"s"}, []string{"t"}) {
+		t.Error("expected no path from s to t after removing the cut")
+	}
+
+	if cut := g.MinCut("t", "s"); cut != nil {
+		t.Errorf("expected nil, got %v", cut)
+	}
+
+	if cut := g.MinCut("s", "s"); cut != nil {
+		t.Errorf("expected nil, got %v", cut)
+	}
+}
+
+func TestMinCutCancel(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: s -> a -> b -> t,
+	// a -> x -> y -> t and s -> p -> q -> b. The first shortest path is
+	// s -> a -> b -> t, which blocks both other paths, so the flow over a -> b
+	// has to be sent back to find the second path.
+	g.Edge("s", "a")
+	g.Edge("a", "b")
+	g.Edge("b", "t")
+	g.Edge("a", "x")
+	g.Edge("x", "y")
+	g.Edge("y", "t")
+	g.Edge("s", "p")
+	g.Edge("p", "q")
+	g.Edge("q", "b")
+
+	cut := g.MinCut("s", "t")
+	expected := [][2]string{{"s", "a"}, {"s", "p"}}
+
+	if !reflect.DeepEqual(cut, expected) {
+		t.Errorf("expected %v, got %v", expected, cut)
+	}
+}
+
+func TestEdgeConnectivity(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: s -> a -> t,
+	// s -> b -> t, s -> c -> b and c -> t. There are three edge-disjoint
+	// paths from s to t, but only one back from t.
+	g.Edge("s", "a")
+	g.Edge("a", "t")
+	g.Edge("s", "b")
+	g.Edge("b", "t")
+	g.Edge("s", "c")
+	g.Edge("c", "b")
+	g.Edge("c", "t")
+	g.Edge("t", "s")
+
+	if n := g.EdgeConnectivity("s", "t"); n != 3 {
+		t.Errorf("expected 3, got %v", n)
+	}
+
+	if n := g.EdgeConnectivity("t", "b"); n != 1 {
+		t.Errorf("expected 1, got %v", n)
+	}
+
+	if n := g.EdgeConnectivity("s", "s"); n != 0 {
+		t.Errorf("expected 0, got %v", n)
+	}
+
+	if n := g.EdgeConnectivity("s", "x"); n != 0 {
+		t.Errorf("expected 0, got %v", n)
+	}
+}
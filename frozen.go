@@ -0,0 +1,769 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"io"
+	"iter"
+	"maps"
+	"math/big"
+)
+
+// FrozenGraph is a read-only view of a directed graph. It only exposes methods
+// that do not modify the graph, so it can be passed to code that must not
+// change the graph.
+type FrozenGraph[Key comparable] struct {
+	g *Graph[Key]
+}
+
+// Freeze returns a read-only view of the graph. The view shares its nodes and
+// edges with the graph, so freezing does not copy anything, but changes to the
+// graph are visible through the view. Use g.Copy().Freeze() for a snapshot
+// that is independent of g.
+func (g *Graph[Key]) Freeze() *FrozenGraph[Key] {
+	return &FrozenGraph[Key]{g: g}
+}
+
+// Copy returns a new, modifiable graph with the same nodes and edges.
+func (f *FrozenGraph[Key]) Copy() *Graph[Key] {
+	return f.g.Copy()
+}
+
+// Reverse returns a new, modifiable graph with all edges reversed.
+func (f *FrozenGraph[Key]) Reverse() *Graph[Key] {
+	return f.g.Reverse()
+}
+
+// Neighbors returns the nodes that are connected to a node by an edge in
+// either direction. See Graph.Neighbors.
+func (f *FrozenGraph[Key]) Neighbors(key Key) []Key {
+	return f.g.Neighbors(key)
+}
+
+// Sort returns a topological sorted list of the graph nodes. See Graph.Sort.
+func (f *FrozenGraph[Key]) Sort() ([]Key, error) {
+	return f.g.Sort()
+}
+
+// SortWithProgress is like Sort, but reports progress. See
+// Graph.SortWithProgress.
+func (f *FrozenGraph[Key]) SortWithProgress(onProgress func(done, total int)) ([]Key, error) {
+	return f.g.SortWithProgress(onProgress)
+}
+
+// SortByOutDegree is like Sort, but emits ready nodes by out-degree. See
+// Graph.SortByOutDegree.
+func (f *FrozenGraph[Key]) SortByOutDegree(descending bool) ([]Key, error) {
+	return f.g.SortByOutDegree(descending)
+}
+
+// FeedbackArcSet returns a set of edges whose removal makes the graph
+// acyclic. See Graph.FeedbackArcSet.
+func (f *FrozenGraph[Key]) FeedbackArcSet() [][2]Key {
+	return f.g.FeedbackArcSet()
+}
+
+// Diamonds returns the diamond dependencies in the graph. See Graph.Diamonds.
+func (f *FrozenGraph[Key]) Diamonds() [][4]Key {
+	return f.g.Diamonds()
+}
+
+// OutDegrees returns the number of outgoing edges of every node. See
+// Graph.OutDegrees.
+func (f *FrozenGraph[Key]) OutDegrees() map[Key]int {
+	return f.g.OutDegrees()
+}
+
+// InDegrees returns the number of incoming edges of every node. See
+// Graph.InDegrees.
+func (f *FrozenGraph[Key]) InDegrees() map[Key]int {
+	return f.g.InDegrees()
+}
+
+// SortTargets is like Sort, but only sorts the given targets and their
+// dependencies. See Graph.SortTargets.
+func (f *FrozenGraph[Key]) SortTargets(targets ...Key) ([]Key, error) {
+	return f.g.SortTargets(targets...)
+}
+
+// IsArticulationPoint reports whether removing a node would disconnect the
+// graph. See Graph.IsArticulationPoint.
+func (f *FrozenGraph[Key]) IsArticulationPoint(key Key) bool {
+	return f.g.IsArticulationPoint(key)
+}
+
+// DFS performs a depth-first traversal from start. See Graph.DFS.
+func (f *FrozenGraph[Key]) DFS(start Key, pre, post func(Key)) {
+	f.g.DFS(start, pre, post)
+}
+
+// BFSLevels returns the distance from start to every reachable node. See
+// Graph.BFSLevels.
+func (f *FrozenGraph[Key]) BFSLevels(start Key) map[Key]int {
+	return f.g.BFSLevels(start)
+}
+
+// HasEdge reports whether the graph has an edge from one node to another. See
+// Graph.HasEdge.
+func (f *FrozenGraph[Key]) HasEdge(from, to Key) bool {
+	return f.g.HasEdge(from, to)
+}
+
+// SortPartial is like Sort, but returns the sorted part of a cyclic graph. See
+// Graph.SortPartial.
+func (f *FrozenGraph[Key]) SortPartial() (sorted []Key, remaining []Key, err error) {
+	return f.g.SortPartial()
+}
+
+// Process calls fn for every node of the graph concurrently, respecting the
+// order of Sort. See Graph.Process.
+func (f *FrozenGraph[Key]) Process(workers int, fn func(Key) error) error {
+	return f.g.Process(workers, fn)
+}
+
+// CytoscapeJSON writes the graph as Cytoscape.js JSON. See
+// Graph.CytoscapeJSON.
+func (f *FrozenGraph[Key]) CytoscapeJSON(w io.Writer) error {
+	return f.g.CytoscapeJSON(w)
+}
+
+// CommonDescendants returns the nodes that can be reached from every given
+// node. See Graph.CommonDescendants.
+func (f *FrozenGraph[Key]) CommonDescendants(keys ...Key) []Key {
+	return f.g.CommonDescendants(keys...)
+}
+
+// RedundantEdges returns the edges implied by longer paths. See
+// Graph.RedundantEdges.
+func (f *FrozenGraph[Key]) RedundantEdges() [][2]Key {
+	return f.g.RedundantEdges()
+}
+
+// Index assigns every node a dense integer id. See Graph.Index.
+func (f *FrozenGraph[Key]) Index() (ids map[Key]int, keys []Key) {
+	return f.g.Index()
+}
+
+// UndeclaredNodes returns the nodes that are not in declared. See
+// Graph.UndeclaredNodes.
+func (f *FrozenGraph[Key]) UndeclaredNodes(declared []Key) []Key {
+	return f.g.UndeclaredNodes(declared)
+}
+
+// SortComponents returns the strongly connected components in topological
+// order. See Graph.SortComponents.
+func (f *FrozenGraph[Key]) SortComponents() ([][]Key, error) {
+	return f.g.SortComponents()
+}
+
+// Fingerprint returns a hash of the nodes and edges of the graph. See
+// Graph.Fingerprint.
+func (f *FrozenGraph[Key]) Fingerprint() uint64 {
+	return f.g.Fingerprint()
+}
+
+// ChangedSince reports whether the fingerprint of the graph differs from fp.
+// See Graph.ChangedSince.
+func (f *FrozenGraph[Key]) ChangedSince(fp uint64) bool {
+	return f.g.ChangedSince(fp)
+}
+
+// CutEdges returns the edges that cross the boundary of a set of nodes. See
+// Graph.CutEdges.
+func (f *FrozenGraph[Key]) CutEdges(set []Key) (in, out [][2]Key) {
+	return f.g.CutEdges(set)
+}
+
+// SortByPriority is like Sort, but emits ready nodes by priority. See
+// Graph.SortByPriority.
+func (f *FrozenGraph[Key]) SortByPriority(priority func(Key) int) ([]Key, error) {
+	return f.g.SortByPriority(priority)
+}
+
+// CollapseChains contracts chains of nodes into single nodes. See
+// Graph.CollapseChains.
+func (f *FrozenGraph[Key]) CollapseChains() (*Graph[int], [][]Key) {
+	return f.g.CollapseChains()
+}
+
+// Canonical returns a sorted text representation of the graph. See
+// Graph.Canonical.
+func (f *FrozenGraph[Key]) Canonical() string {
+	return f.g.Canonical()
+}
+
+// PageRank returns the PageRank of every node. See Graph.PageRank.
+func (f *FrozenGraph[Key]) PageRank(damping float64, iterations int) map[Key]float64 {
+	return f.g.PageRank(damping, iterations)
+}
+
+// IsValidOrder reports whether order is a topological order of the graph. See
+// Graph.IsValidOrder.
+func (f *FrozenGraph[Key]) IsValidOrder(order []Key) bool {
+	return f.g.IsValidOrder(order)
+}
+
+// Sinks returns the nodes without outgoing edges. See Graph.Sinks.
+func (f *FrozenGraph[Key]) Sinks() []Key {
+	return f.g.Sinks()
+}
+
+// NonTerminating returns the nodes from which no sink can be reached. See
+// Graph.NonTerminating.
+func (f *FrozenGraph[Key]) NonTerminating() []Key {
+	return f.g.NonTerminating()
+}
+
+// Neighborhood returns the subgraph of the nodes near center. See
+// Graph.Neighborhood.
+func (f *FrozenGraph[Key]) Neighborhood(center Key, radius int) *Graph[Key] {
+	return f.g.Neighborhood(center, radius)
+}
+
+// SortedSeq returns an iterator over the nodes in topological order. See
+// Graph.SortedSeq.
+func (f *FrozenGraph[Key]) SortedSeq() (iter.Seq[Key], error) {
+	return f.g.SortedSeq()
+}
+
+// MinEdgesToStronglyConnect returns the minimum number of edges needed to make
+// the graph strongly connected. See Graph.MinEdgesToStronglyConnect.
+func (f *FrozenGraph[Key]) MinEdgesToStronglyConnect() int {
+	return f.g.MinEdgesToStronglyConnect()
+}
+
+// BackEdges returns the back edges of a depth-first search over the graph. See
+// Graph.BackEdges.
+func (f *FrozenGraph[Key]) BackEdges() [][2]Key {
+	return f.g.BackEdges()
+}
+
+// SortSeeded returns a topological order of the graph, breaking ties by the
+// order of seed. See Graph.SortSeeded.
+func (f *FrozenGraph[Key]) SortSeeded(seed []Key) ([]Key, error) {
+	return f.g.SortSeeded(seed)
+}
+
+// ReductionEdges returns the edges of the transitive reduction of the graph.
+// See Graph.ReductionEdges.
+func (f *FrozenGraph[Key]) ReductionEdges() ([][2]Key, error) {
+	return f.g.ReductionEdges()
+}
+
+// CycleReport describes every cycle in the graph. See Graph.CycleReport.
+func (f *FrozenGraph[Key]) CycleReport() ([]CycleInfo[Key], error) {
+	return f.g.CycleReport()
+}
+
+// TopologicalOrders returns an iterator over all topological orders of the
+// graph. See Graph.TopologicalOrders.
+func (f *FrozenGraph[Key]) TopologicalOrders() iter.Seq[[]Key] {
+	return f.g.TopologicalOrders()
+}
+
+// WalkDepth walks the tree of paths from start up to a maximum depth. See
+// Graph.WalkDepth.
+func (f *FrozenGraph[Key]) WalkDepth(start Key, maxDepth int, visit func(key Key, depth int) bool) {
+	f.g.WalkDepth(start, maxDepth, visit)
+}
+
+// SCCKosaraju returns the strongly connected components of the graph, using
+// Kosaraju's algorithm. See Graph.SCCKosaraju.
+func (f *FrozenGraph[Key]) SCCKosaraju() [][]Key {
+	return f.g.SCCKosaraju()
+}
+
+// Ranks assigns every node a rank by the graph of its strongly connected
+// components. See Graph.Ranks.
+func (f *FrozenGraph[Key]) Ranks() map[Key]int {
+	return f.g.Ranks()
+}
+
+// SplitAt returns the connected components of the graph without the cut
+// nodes. See Graph.SplitAt.
+func (f *FrozenGraph[Key]) SplitAt(cuts []Key) []*Graph[Key] {
+	return f.g.SplitAt(cuts)
+}
+
+// IsPolytree reports whether the graph is a polytree. See Graph.IsPolytree.
+func (f *FrozenGraph[Key]) IsPolytree() bool {
+	return f.g.IsPolytree()
+}
+
+// Lookup returns a copy of the edges for a node, and whether the node exists.
+// The edges are copied, so they cannot be used to modify the graph. See
+// Graph.Lookup.
+func (f *FrozenGraph[Key]) Lookup(key Key) (Edges[Key], bool) {
+	n, ok := f.g.Lookup(key)
+	return maps.Clone(n), ok
+}
+
+// ReachableAny reports whether any of the targets can be reached from any of
+// the sources. See Graph.ReachableAny.
+func (f *FrozenGraph[Key]) ReachableAny(sources, targets []Key) bool {
+	return f.g.ReachableAny(sources, targets)
+}
+
+// WalkLayers calls visit for every layer of the graph, in topological order.
+// See Graph.WalkLayers.
+func (f *FrozenGraph[Key]) WalkLayers(visit func(layer []Key) error) error {
+	return f.g.WalkLayers(visit)
+}
+
+// MergeStrict returns a new graph with the nodes and edges of both graphs, and
+// reports cycles created by the merge. See Graph.MergeStrict.
+func (f *FrozenGraph[Key]) MergeStrict(other *Graph[Key]) (*Graph[Key], error) {
+	return f.g.MergeStrict(other)
+}
+
+// AdjacencyList returns the edges of the graph as a map from every node to the
+// nodes it has an edge to. See Graph.AdjacencyList.
+func (f *FrozenGraph[Key]) AdjacencyList() map[Key][]Key {
+	return f.g.AdjacencyList()
+}
+
+// Width returns the size of the largest antichain of the graph. See
+// Graph.Width.
+func (f *FrozenGraph[Key]) Width() (int, error) {
+	return f.g.Width()
+}
+
+// Between returns the nodes that are on a path from one node to another. See
+// Graph.Between.
+func (f *FrozenGraph[Key]) Between(from, to Key) []Key {
+	return f.g.Between(from, to)
+}
+
+// DependencyCounts returns the number of nodes that can be reached from every
+// node. See Graph.DependencyCounts.
+func (f *FrozenGraph[Key]) DependencyCounts() (map[Key]int, error) {
+	return f.g.DependencyCounts()
+}
+
+// EdgeDirection reports whether the graph has an edge in either direction
+// between two nodes. See Graph.EdgeDirection.
+func (f *FrozenGraph[Key]) EdgeDirection(a, b Key) (aToB, bToA bool) {
+	return f.g.EdgeDirection(a, b)
+}
+
+// MinimalEquivalent returns the graph with the fewest edges that has the same
+// topological orders as the graph. See Graph.MinimalEquivalent.
+func (f *FrozenGraph[Key]) MinimalEquivalent() (*Graph[Key], error) {
+	return f.g.MinimalEquivalent()
+}
+
+// SortDependenciesFirst returns a topological order of the graph in which
+// every node comes after the nodes it has an edge to. See
+// Graph.SortDependenciesFirst.
+func (f *FrozenGraph[Key]) SortDependenciesFirst() ([]Key, error) {
+	return f.g.SortDependenciesFirst()
+}
+
+// Diameter returns the largest shortest distance between two nodes of the
+// graph. See Graph.Diameter.
+func (f *FrozenGraph[Key]) Diameter() int {
+	return f.g.Diameter()
+}
+
+// MutualEdges returns the pairs of nodes that have an edge to each other. See
+// Graph.MutualEdges.
+func (f *FrozenGraph[Key]) MutualEdges() [][2]Key {
+	return f.g.MutualEdges()
+}
+
+// IsSymmetric reports whether the graph has an edge in the other direction for
+// every edge. See Graph.IsSymmetric.
+func (f *FrozenGraph[Key]) IsSymmetric() bool {
+	return f.g.IsSymmetric()
+}
+
+// GroupBy returns a new graph in which the nodes are replaced by their
+// groups. See Graph.GroupBy.
+func (f *FrozenGraph[Key]) GroupBy(group func(Key) Key) *Graph[Key] {
+	return f.g.GroupBy(group)
+}
+
+// TopologicalRanks returns the position of every node in a topological order
+// of the graph. See Graph.TopologicalRanks.
+func (f *FrozenGraph[Key]) TopologicalRanks() (map[Key]int, error) {
+	return f.g.TopologicalRanks()
+}
+
+// ShortestCycleThrough returns the nodes of a shortest cycle through a node.
+// See Graph.ShortestCycleThrough.
+func (f *FrozenGraph[Key]) ShortestCycleThrough(key Key) ([]Key, bool) {
+	return f.g.ShortestCycleThrough(key)
+}
+
+// CondensationDOT writes the strongly connected components of the graph in
+// the Graphviz DOT language. See Graph.CondensationDOT.
+func (f *FrozenGraph[Key]) CondensationDOT(w io.Writer) error {
+	return f.g.CondensationDOT(w)
+}
+
+// ReachableCount returns the number of nodes that can be reached from a node.
+// See Graph.ReachableCount.
+func (f *FrozenGraph[Key]) ReachableCount(key Key) int {
+	return f.g.ReachableCount(key)
+}
+
+// ReverseTopologicalSeq returns an iterator over the nodes of the graph in
+// reverse topological order. See Graph.ReverseTopologicalSeq.
+func (f *FrozenGraph[Key]) ReverseTopologicalSeq() (iter.Seq[Key], error) {
+	return f.g.ReverseTopologicalSeq()
+}
+
+// MinCut returns a smallest set of edges whose removal leaves no path from one
+// node to another. See Graph.MinCut.
+func (f *FrozenGraph[Key]) MinCut(from, to Key) [][2]Key {
+	return f.g.MinCut(from, to)
+}
+
+// Violations returns the edges for which allowed returns false. See
+// Graph.Violations.
+func (f *FrozenGraph[Key]) Violations(allowed func(from, to Key) bool) [][2]Key {
+	return f.g.Violations(allowed)
+}
+
+// SCCSizes returns the sizes of the strongly connected components of the
+// graph. See Graph.SCCSizes.
+func (f *FrozenGraph[Key]) SCCSizes() []int {
+	return f.g.SCCSizes()
+}
+
+// NodeLinkJSON writes the graph as JSON in the node-link format of NetworkX.
+// See Graph.NodeLinkJSON.
+func (f *FrozenGraph[Key]) NodeLinkJSON(w io.Writer) error {
+	return f.g.NodeLinkJSON(w)
+}
+
+// HasUniqueTopologicalOrder reports whether the graph has exactly one
+// topological order. See Graph.HasUniqueTopologicalOrder.
+func (f *FrozenGraph[Key]) HasUniqueTopologicalOrder() (bool, error) {
+	return f.g.HasUniqueTopologicalOrder()
+}
+
+// ImpliedEdges returns the nodes that can be reached from a node, but that it
+// has no edge to. See Graph.ImpliedEdges.
+func (f *FrozenGraph[Key]) ImpliedEdges(key Key) []Key {
+	return f.g.ImpliedEdges(key)
+}
+
+// FeedbackVertexSet returns a set of nodes whose removal makes the graph
+// acyclic. See Graph.FeedbackVertexSet.
+func (f *FrozenGraph[Key]) FeedbackVertexSet() []Key {
+	return f.g.FeedbackVertexSet()
+}
+
+// ReachabilityBitsets returns the nodes every node can reach as bitsets. See
+// Graph.ReachabilityBitsets.
+func (f *FrozenGraph[Key]) ReachabilityBitsets() (map[Key]*big.Int, []Key) {
+	return f.g.ReachabilityBitsets()
+}
+
+// CanAddEdges reports whether adding all edges at once would keep the graph
+// free of new cycles. See Graph.CanAddEdges.
+func (f *FrozenGraph[Key]) CanAddEdges(edges [][2]Key) (bool, [][2]Key) {
+	return f.g.CanAddEdges(edges)
+}
+
+// Influence returns the fraction of the other nodes every node can reach. See
+// Graph.Influence.
+func (f *FrozenGraph[Key]) Influence() (map[Key]float64, error) {
+	return f.g.Influence()
+}
+
+// EdgeColumns returns the edges as two parallel slices. See Graph.EdgeColumns.
+func (f *FrozenGraph[Key]) EdgeColumns() (from []Key, to []Key) {
+	return f.g.EdgeColumns()
+}
+
+// FirstCommonAncestor returns the nearest common ancestor of two nodes. See
+// Graph.FirstCommonAncestor.
+func (f *FrozenGraph[Key]) FirstCommonAncestor(a, b Key) (Key, bool) {
+	return f.g.FirstCommonAncestor(a, b)
+}
+
+// MaxParallelism returns the size of the largest layer. See
+// Graph.MaxParallelism.
+func (f *FrozenGraph[Key]) MaxParallelism() (int, error) {
+	return f.g.MaxParallelism()
+}
+
+// MaximalChains returns every path from a node without incoming edges to a
+// node without outgoing edges. See Graph.MaximalChains.
+func (f *FrozenGraph[Key]) MaximalChains() ([][]Key, error) {
+	return f.g.MaximalChains()
+}
+
+// ReduceAround returns a copy of the graph without the redundant edges around
+// the given nodes. See Graph.ReduceAround.
+func (f *FrozenGraph[Key]) ReduceAround(keys []Key) (*Graph[Key], error) {
+	return f.g.ReduceAround(keys)
+}
+
+// DanglingEdges returns the edges whose target is not a node. See
+// Graph.DanglingEdges.
+func (f *FrozenGraph[Key]) DanglingEdges() [][2]Key {
+	return f.g.DanglingEdges()
+}
+
+// CriticalEdges returns the edges on a longest path. See Graph.CriticalEdges.
+func (f *FrozenGraph[Key]) CriticalEdges() ([][2]Key, error) {
+	return f.g.CriticalEdges()
+}
+
+// WriteSorted writes the nodes in topological order to w. See
+// Graph.WriteSorted.
+func (f *FrozenGraph[Key]) WriteSorted(w io.Writer, sep string) error {
+	return f.g.WriteSorted(w, sep)
+}
+
+// IsomorphicTo reports whether the graph has the same structure as other. See
+// Graph.IsomorphicTo.
+func (f *FrozenGraph[Key]) IsomorphicTo(other *Graph[Key]) bool {
+	return f.g.IsomorphicTo(other)
+}
+
+// TopByInDegree returns the n nodes with the most incoming edges. See
+// Graph.TopByInDegree.
+func (f *FrozenGraph[Key]) TopByInDegree(n int) []Key {
+	return f.g.TopByInDegree(n)
+}
+
+// EdgeCriticality returns the number of pairs of nodes that are no longer
+// connected without an edge. See Graph.EdgeCriticality.
+func (f *FrozenGraph[Key]) EdgeCriticality(from, to Key) int {
+	return f.g.EdgeCriticality(from, to)
+}
+
+// Canonicalize returns a copy of the graph with nodes numbered by structure.
+// See Graph.Canonicalize.
+func (f *FrozenGraph[Key]) Canonicalize() *Graph[int] {
+	return f.g.Canonicalize()
+}
+
+// BestRoot returns the node without incoming edges that reaches the most
+// nodes. See Graph.BestRoot.
+func (f *FrozenGraph[Key]) BestRoot() (Key, int) {
+	return f.g.BestRoot()
+}
+
+// Layout returns a position on a grid for every node. See Graph.Layout.
+func (f *FrozenGraph[Key]) Layout() (map[Key][2]int, error) {
+	return f.g.Layout()
+}
+
+// EdgeConnectivity returns the number of edge-disjoint paths from one node to
+// another. See Graph.EdgeConnectivity.
+func (f *FrozenGraph[Key]) EdgeConnectivity(from, to Key) int {
+	return f.g.EdgeConnectivity(from, to)
+}
+
+// SortSplit returns a topological order split at a node. See Graph.SortSplit.
+func (f *FrozenGraph[Key]) SortSplit(pivot Key) (before []Key, after []Key, err error) {
+	return f.g.SortSplit(pivot)
+}
+
+// SCCOf returns the id of the strongly connected component of every node. See
+// Graph.SCCOf.
+func (f *FrozenGraph[Key]) SCCOf() map[Key]int {
+	return f.g.SCCOf()
+}
+
+// PathsFromRoots returns every path from a node without incoming edges to a
+// node. See Graph.PathsFromRoots.
+func (f *FrozenGraph[Key]) PathsFromRoots(target Key) [][]Key {
+	return f.g.PathsFromRoots(target)
+}
+
+// Diff returns the nodes and edges added and removed in other. See Graph.Diff.
+func (f *FrozenGraph[Key]) Diff(other *Graph[Key]) (addedNodes, removedNodes []Key, addedEdges, removedEdges [][2]Key) {
+	return f.g.Diff(other)
+}
+
+// ScheduleRounds divides the nodes into rounds of at most workers nodes. See
+// Graph.ScheduleRounds.
+func (f *FrozenGraph[Key]) ScheduleRounds(workers int) ([][]Key, error) {
+	return f.g.ScheduleRounds(workers)
+}
+
+// IsSourceSink reports whether the graph is acyclic with a single source and a
+// single sink. See Graph.IsSourceSink.
+func (f *FrozenGraph[Key]) IsSourceSink() (source, sink Key, ok bool) {
+	return f.g.IsSourceSink()
+}
+
+// Dominators returns the immediate dominator of every node that can be
+// reached from entry. See Graph.Dominators.
+func (f *FrozenGraph[Key]) Dominators(entry Key) map[Key]Key {
+	return f.g.Dominators(entry)
+}
+
+// PostDominators returns the immediate post-dominator of every node from which
+// exit can be reached. See Graph.PostDominators.
+func (f *FrozenGraph[Key]) PostDominators(exit Key) map[Key]Key {
+	return f.g.PostDominators(exit)
+}
+
+// MinimumRootCover returns a smallest set of nodes from which every node can
+// be reached. See Graph.MinimumRootCover.
+func (f *FrozenGraph[Key]) MinimumRootCover() []Key {
+	return f.g.MinimumRootCover()
+}
+
+// Sample returns a smaller graph with a random fraction of the nodes and the
+// same reachability between them. See Graph.Sample.
+func (f *FrozenGraph[Key]) Sample(keep float64, seed int64) *Graph[Key] {
+	return f.g.Sample(keep, seed)
+}
+
+// Comparable reports whether one of two nodes can be reached from the other.
+// See Graph.Comparable.
+func (f *FrozenGraph[Key]) Comparable(a, b Key) bool {
+	return f.g.Comparable(a, b)
+}
+
+// ImpactOf returns the nodes that depend on any of the changed nodes. See
+// Graph.ImpactOf.
+func (f *FrozenGraph[Key]) ImpactOf(changed ...Key) []Key {
+	return f.g.ImpactOf(changed...)
+}
+
+// Quotient returns a new graph in which every node is merged into its
+// representative. See Graph.Quotient.
+func (f *FrozenGraph[Key]) Quotient(rep func(Key) Key) (*Graph[Key], map[Key]Key) {
+	return f.g.Quotient(rep)
+}
+
+// MaxDepth returns the number of edges of the longest path. See
+// Graph.MaxDepth.
+func (f *FrozenGraph[Key]) MaxDepth() (int, error) {
+	return f.g.MaxDepth()
+}
+
+// Bridges returns the edges whose removal disconnects the graph, when the
+// direction of the edges is ignored. See Graph.Bridges.
+func (f *FrozenGraph[Key]) Bridges() [][2]Key {
+	return f.g.Bridges()
+}
+
+// SpanningTree returns a breadth-first tree of the nodes reachable from root.
+// See Graph.SpanningTree.
+func (f *FrozenGraph[Key]) SpanningTree(root Key) (*Graph[Key], []Key) {
+	return f.g.SpanningTree(root)
+}
+
+// BackEdgeCount returns the number of edges that point backwards in an order.
+// See Graph.BackEdgeCount.
+func (f *FrozenGraph[Key]) BackEdgeCount(order []Key) int {
+	return f.g.BackEdgeCount(order)
+}
+
+// ComponentsSeq returns an iterator over the connected components, when the
+// direction of the edges is ignored. See Graph.ComponentsSeq.
+func (f *FrozenGraph[Key]) ComponentsSeq() iter.Seq[[]Key] {
+	return f.g.ComponentsSeq()
+}
+
+// LayerEdgeCounts returns the number of edges that start in every layer. See
+// Graph.LayerEdgeCounts.
+func (f *FrozenGraph[Key]) LayerEdgeCounts() ([]int, error) {
+	return f.g.LayerEdgeCounts()
+}
+
+// ReachableWithin reports whether a node can be reached from another by
+// following at most k edges. See Graph.ReachableWithin.
+func (f *FrozenGraph[Key]) ReachableWithin(from, to Key, k int) bool {
+	return f.g.ReachableWithin(from, to, k)
+}
+
+// DominanceFrontier returns the dominance frontier of every node that can be
+// reached from entry. See Graph.DominanceFrontier.
+func (f *FrozenGraph[Key]) DominanceFrontier(entry Key) map[Key][]Key {
+	return f.g.DominanceFrontier(entry)
+}
+
+// HierarchicalJSON writes the graph as JSON grouped by strongly connected
+// components. See Graph.HierarchicalJSON.
+func (f *FrozenGraph[Key]) HierarchicalJSON(w io.Writer) error {
+	return f.g.HierarchicalJSON(w)
+}
+
+// BlastRadii returns the number of nodes that depend on every node. See
+// Graph.BlastRadii.
+func (f *FrozenGraph[Key]) BlastRadii() (map[Key]int, error) {
+	return f.g.BlastRadii()
+}
+
+// OutDegreeViolations returns the nodes with more than limit outgoing edges.
+// See Graph.OutDegreeViolations.
+func (f *FrozenGraph[Key]) OutDegreeViolations(limit int) []Key {
+	return f.g.OutDegreeViolations(limit)
+}
+
+// StructuralHashes returns a hash for every node that depends on the nodes it
+// can reach. See Graph.StructuralHashes.
+func (f *FrozenGraph[Key]) StructuralHashes() (map[Key]uint64, error) {
+	return f.g.StructuralHashes()
+}
+
+// FirstCommonDescendant returns the nearest node that can be reached from both
+// a and b. See Graph.FirstCommonDescendant.
+func (f *FrozenGraph[Key]) FirstCommonDescendant(a, b Key) (Key, bool) {
+	return f.g.FirstCommonDescendant(a, b)
+}
+
+// RelativeTo splits all nodes but pivot by how they are ordered relative to
+// it. See Graph.RelativeTo.
+func (f *FrozenGraph[Key]) RelativeTo(pivot Key) (before, after, unordered []Key) {
+	return f.g.RelativeTo(pivot)
+}
+
+// Reachability returns a Reachability for the graph, which computes what every
+// node reaches as it is needed. See Graph.Reachability.
+func (f *FrozenGraph[Key]) Reachability() *Reachability[Key] {
+	return f.g.Reachability()
+}
+
+// IsTotalOrder reports whether every two distinct nodes are ordered by the
+// graph. See Graph.IsTotalOrder.
+func (f *FrozenGraph[Key]) IsTotalOrder() (bool, error) {
+	return f.g.IsTotalOrder()
+}
+
+// Heights returns the number of edges on the longest path from every node to a
+// node without outgoing edges. See Graph.Heights.
+func (f *FrozenGraph[Key]) Heights() (map[Key]int, error) {
+	return f.g.Heights()
+}
+
+// AtDistance returns the nodes whose distance from from is exactly d edges.
+// See Graph.AtDistance.
+func (f *FrozenGraph[Key]) AtDistance(from Key, d int) []Key {
+	return f.g.AtDistance(from, d)
+}
+
+// SortWithHints is like Sort, but also follows the hints where the edges of the
+// graph leave a choice. See Graph.SortWithHints.
+func (f *FrozenGraph[Key]) SortWithHints(hints [][2]Key) ([]Key, error) {
+	return f.g.SortWithHints(hints)
+}
+
+// NonCriticalEdges returns the edges that are not on any longest path of the
+// graph. See Graph.NonCriticalEdges.
+func (f *FrozenGraph[Key]) NonCriticalEdges() ([][2]Key, error) {
+	return f.g.NonCriticalEdges()
+}
+
+// ClusteringCoefficient returns the average local clustering coefficient of
+// the graph. See Graph.ClusteringCoefficient.
+func (f *FrozenGraph[Key]) ClusteringCoefficient() float64 {
+	return f.g.ClusteringCoefficient()
+}
+
+// InvalidationFrontier returns the nodes from which to evaluate again after the
+// dirty nodes changed. See Graph.InvalidationFrontier.
+func (f *FrozenGraph[Key]) InvalidationFrontier(dirty []Key) []Key {
+	return f.g.InvalidationFrontier(dirty)
+}
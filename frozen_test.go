@@ -0,0 +1,53 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	g := New[string]()
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+
+	f := g.Freeze()
+
+	keys, err := f.Sort()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("expected [a b c], got %v", keys)
+	}
+
+	// Querying a node that does not exist must not create it.
+	if neighbors := f.Neighbors("x"); neighbors != nil {
+		t.Errorf("expected nil, got %v", neighbors)
+	}
+
+	if len(g.nodes) != 3 {
+		t.Errorf("expected 3 nodes, got %v", len(g.nodes))
+	}
+
+	// Modifying the edges returned by Lookup must not modify the graph.
+	if e, ok := f.Lookup("c"); ok {
+		e.add("a")
+	}
+
+	if g.HasEdge("c", "a") {
+		t.Error("expected no edge c -> a")
+	}
+
+	// Modifying a copy of the frozen graph must not modify the graph.
+	c := f.Copy()
+	c.Edge("c", "d")
+
+	if len(g.nodes) != 3 {
+		t.Errorf("expected 3 nodes, got %v", len(g.nodes))
+	}
+}
@@ -11,11 +11,49 @@
 //	s, err := g.Sort() // []string{"b", "c", "a"}
 package graph
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+var (
+	// ErrCycle is returned by methods that require the graph to be acyclic
+	// when the graph has a cycle.
+	ErrCycle = errors.New("cycle detected")
+
+	// ErrNotFound is returned by methods that require a node to exist when it
+	// does not.
+	ErrNotFound = errors.New("node not found")
+
+	// ErrExists is returned by methods that require a node not to exist when
+	// it does.
+	ErrExists = errors.New("node already exists")
+)
 
 // Graph represents a directed graph.
 type Graph[Key comparable] struct {
 	nodes map[Key]Edges[Key]
+
+	// normalize canonicalizes keys, see SetKeyNormalizer.
+	normalize func(Key) Key
+
+	// dirty is set when the graph changes, see Dirty.
+	dirty bool
+
+	// gen is incremented when the graph changes, so caches built from the
+	// graph can tell that they are outdated, see Reachability.
+	gen uint64
+
+	// indegree is the number of incoming edges of every node, if the degree
+	// cache is enabled, see EnableDegreeCache.
+	indegree map[Key]int
+
+	// order is a topological order of the graph, and pos the positions of
+	// the nodes in it, kept up to date by EdgeChecked. They are reset when
+	// the graph changes in any other way.
+	order []Key
+	pos   map[Key]int
 }
 
 // Edges represents the edges of a node in a directed graph.
@@ -28,125 +66,575 @@ func New[Key comparable]() *Graph[Key] {
 	}
 }
 
-// Node returns the edges for a node. It creates the node if it does not exist.
+// FromRequires returns a new graph built from a map of nodes to their direct
+// dependencies, such as the requirements of the modules in a go.mod file. It
+// adds every node with an edge to each of its dependencies, like Add, so the
+// dependencies become nodes too.
+func FromRequires(requires map[string][]string) *Graph[string] {
+	g := New[string]()
+	for node, deps := range requires {
+		g.Add(node, deps)
+	}
+	return g
+}
+
+// Validate returns a new graph with the given nodes and edges. Unlike Edge and
+// Add, it does not create the nodes of the edges: it returns an error wrapping
+// ErrNotFound if an edge refers to a node that is not in nodes, and an error
+// wrapping ErrExists if a node is listed twice. This catches dangling
+// references in graphs read from an untrusted source.
+func Validate[Key comparable](nodes []Key, edges [][2]Key) (*Graph[Key], error) {
+	g := New[Key]()
+
+	for _, k := range nodes {
+		if _, ok := g.nodes[k]; ok {
+			return nil, fmt.Errorf("%w: %v", ErrExists, k)
+		}
+		g.node(k)
+	}
+
+	for _, e := range edges {
+		for _, k := range e {
+			if _, ok := g.nodes[k]; !ok {
+				return nil, fmt.Errorf("%w: %v", ErrNotFound, k)
+			}
+		}
+		g.link(e[0], e[1])
+	}
+
+	return g, nil
+}
+
+// SetKeyNormalizer sets a function that canonicalizes keys, for example by
+// lowercasing them. It is applied to every key passed to the methods of the
+// graph, both when adding and when looking up nodes, so keys that normalize to
+// the same key refer to the same node. Existing nodes are normalized too,
+// merging nodes and edges where needed. The function must be idempotent:
+// normalizing a normalized key must return the same key. Passing nil removes
+// the normalizer.
+func (g *Graph[Key]) SetKeyNormalizer(fn func(Key) Key) {
+	g.normalize = fn
+	if fn == nil {
+		return
+	}
+
+	nodes := g.nodes
+	g.nodes = make(map[Key]Edges[Key], len(nodes))
+
+	for from, e := range nodes {
+		f := fn(from)
+		g.node(f)
+		for to := range e {
+			t := fn(to)
+			g.node(t)
+			g.link(f, t)
+		}
+	}
+
+	// Merged edges are only counted once, so we count the incoming edges of
+	// the normalized graph again.
+	if g.indegree != nil {
+		g.indegree = nil
+		g.EnableDegreeCache()
+	}
+
+	g.changed()
+}
+
+// key returns the normalized key, using the normalizer set with
+// SetKeyNormalizer.
+func (g *Graph[Key]) key(key Key) Key {
+	if g.normalize == nil {
+		return key
+	}
+	return g.normalize(key)
+}
+
+// Node returns the edges for a node. It creates the node if it does not exist,
+// so it is meant for adding nodes. Use Lookup to read the edges of a node,
+// which does not create a node for a key that was mistyped.
 func (g *Graph[Key]) Node(key Key) Edges[Key] {
+	return g.node(g.key(key))
+}
+
+// Lookup returns the edges for a node, and whether the node exists. Unlike
+// Node, it never creates a node.
+func (g *Graph[Key]) Lookup(key Key) (Edges[Key], bool) {
+	n, ok := g.nodes[g.key(key)]
+	return n, ok
+}
+
+// node returns the edges for a node, without normalizing the key. It creates
+// the node if it does not exist.
+func (g *Graph[Key]) node(key Key) Edges[Key] {
 	n, ok := g.nodes[key]
 	if !ok {
 		n = make(Edges[Key])
 		g.nodes[key] = n
+		if g.indegree != nil {
+			g.indegree[key] = 0
+		}
+		g.changed()
 	}
 	return n
 }
 
+// link adds an edge between two existing nodes, without normalizing the keys.
+func (g *Graph[Key]) link(from, to Key) {
+	if e := g.nodes[from]; !e[to] {
+		e.add(to)
+		if g.indegree != nil {
+			g.indegree[to]++
+		}
+		g.changed()
+	}
+}
+
+// unlink removes an edge between two existing nodes, without normalizing the
+// keys.
+func (g *Graph[Key]) unlink(from, to Key) {
+	if e := g.nodes[from]; e[to] {
+		delete(e, to)
+		if g.indegree != nil {
+			g.indegree[to]--
+		}
+		g.changed()
+	}
+}
+
+// changed records that the graph changed. Every method that changes the graph
+// calls it.
+func (g *Graph[Key]) changed() {
+	g.dirty = true
+	g.gen++
+	g.order, g.pos = nil, nil
+}
+
+// EnableDegreeCache makes the graph keep track of the number of incoming edges
+// of every node as nodes and edges are added and removed. Sort and the other
+// methods that need the in-degrees of all nodes, such as InDegrees, then copy
+// the cached counts instead of scanning all edges, which makes many small
+// changes interleaved with sorts cheaper. The cache costs one map entry per
+// node. Like Dirty, it does not track changes made directly to the Edges
+// returned by Node or Lookup. Enabling the cache when it is already enabled
+// does nothing.
+func (g *Graph[Key]) EnableDegreeCache() {
+	if g.indegree != nil {
+		return
+	}
+
+	g.indegree = g.InDegrees()
+}
+
+// Dirty reports whether the graph changed since it was created or since the
+// last call to MarkClean. Adding a node or an edge that already exists does
+// not change the graph. Changes made directly to the Edges returned by Node
+// are not tracked.
+func (g *Graph[Key]) Dirty() bool {
+	return g.dirty
+}
+
+// MarkClean marks the graph as unchanged, so Dirty returns false until the
+// graph changes again.
+func (g *Graph[Key]) MarkClean() {
+	g.dirty = false
+}
+
 // Edge adds an edge to the graph. It creates the nodes if they do not exist.
 func (g *Graph[Key]) Edge(from Key, to Key) {
-	f := g.Node(from)
-	g.Node(to)
-	f.add(to)
+	from, to = g.key(from), g.key(to)
+
+	g.node(from)
+	g.node(to)
+	g.link(from, to)
 }
 
 // Add adds a node and its outgoing edges to the graph.
 func (g *Graph[Key]) Add(node Key, edges []Key) {
-	n := g.Node(node)
+	node = g.key(node)
+	g.node(node)
 	for _, e := range edges {
-		g.Node(e)
-		n.add(e)
+		e = g.key(e)
+		g.node(e)
+		g.link(node, e)
+	}
+}
+
+// AddSuperSink adds an edge from every node without outgoing edges to sink,
+// creating sink if it does not exist, so sink becomes the only node without
+// outgoing edges. Many algorithms are simpler on a graph with a single end.
+// It returns the normalized key of sink. If sink already exists, its own
+// edges are kept, and it only becomes the only sink if it has no outgoing
+// edges itself.
+func (g *Graph[Key]) AddSuperSink(sink Key) Key {
+	sink = g.key(sink)
+
+	sinks := g.Sinks()
+	g.node(sink)
+	for _, k := range sinks {
+		if k != sink {
+			g.link(k, sink)
+		}
+	}
+
+	return sink
+}
+
+// AddSuperSource adds an edge from source to every node without incoming
+// edges, creating source if it does not exist, so source becomes the only
+// node without incoming edges. It is the counterpart of AddSuperSink, and
+// returns the normalized key of source. If source already exists, its own
+// edges are kept, and it only becomes the only source if it has no incoming
+// edges itself.
+func (g *Graph[Key]) AddSuperSource(source Key) Key {
+	source = g.key(source)
+
+	indegree := g.InDegrees()
+	g.node(source)
+	for k, d := range indegree {
+		if d == 0 && k != source {
+			g.link(source, k)
+		}
 	}
+
+	return source
 }
 
-// Reverse returns a new graph with all edges reversed.
+// HasEdge reports whether the graph has an edge from one node to another.
+func (g *Graph[Key]) HasEdge(from, to Key) bool {
+	return g.nodes[g.key(from)][g.key(to)]
+}
+
+// EdgeDirection reports whether the graph has an edge from a to b and whether
+// it has an edge from b to a. If both are true, a and b form a cycle.
+func (g *Graph[Key]) EdgeDirection(a, b Key) (aToB, bToA bool) {
+	a, b = g.key(a), g.key(b)
+	return g.nodes[a][b], g.nodes[b][a]
+}
+
+// RemoveMatching removes every node for which pred returns true, together with
+// all edges from and to those nodes, and returns the number of removed nodes.
+// It removes all nodes in a single scan over the graph, so it takes O(n) time
+// for n = [number of nodes] + [number of edges], regardless of the number of
+// removed nodes.
+func (g *Graph[Key]) RemoveMatching(pred func(Key) bool) int {
+	removed := make(map[Key]bool)
+	for k := range g.nodes {
+		if pred(k) {
+			removed[k] = true
+		}
+	}
+
+	if len(removed) == 0 {
+		return 0
+	}
+
+	for k := range removed {
+		for to := range g.nodes[k] {
+			if g.indegree != nil {
+				g.indegree[to]--
+			}
+		}
+		delete(g.nodes, k)
+		delete(g.indegree, k)
+	}
+
+	for _, e := range g.nodes {
+		for to := range e {
+			if removed[to] {
+				delete(e, to)
+			}
+		}
+	}
+
+	g.changed()
+
+	return len(removed)
+}
+
+// UndeclaredNodes returns the nodes of the graph that are not in declared, in
+// a deterministic order. Edge and Add create the nodes that edges point to, so
+// this finds the nodes that only exist because an edge references them, when
+// declared are the nodes that were added on purpose.
+func (g *Graph[Key]) UndeclaredNodes(declared []Key) []Key {
+	known := make(map[Key]bool, len(declared))
+	for _, k := range declared {
+		known[g.key(k)] = true
+	}
+
+	var undeclared []Key
+	for k := range g.nodes {
+		if !known[k] {
+			undeclared = append(undeclared, k)
+		}
+	}
+	sortKeys(undeclared)
+
+	return undeclared
+}
+
+// DanglingEdges returns the edges whose target is not a node of the graph, in
+// a deterministic order. Edge and Add always create the target, so these can
+// only exist if the edges returned by Node or Lookup were modified directly.
+// Most methods assume there are none, so a non-empty result indicates a bug;
+// it is meant as a consistency check in tests and assertions.
+func (g *Graph[Key]) DanglingEdges() [][2]Key {
+	var dangling [][2]Key
+	for from, e := range g.nodes {
+		for to := range e {
+			if _, ok := g.nodes[to]; !ok {
+				dangling = append(dangling, [2]Key{from, to})
+			}
+		}
+	}
+
+	sortEdges(dangling)
+
+	return dangling
+}
+
+// Rename changes the key of a node, both as a node and as the target of edges,
+// keeping all its edges. It returns an error wrapping ErrNotFound if the node
+// does not exist, and an error wrapping ErrExists if a node with the new key
+// already exists, as renaming it would silently merge the two nodes. Renaming
+// requires a scan over all nodes to update the edges to the node.
+func (g *Graph[Key]) Rename(old, new Key) error {
+	old, new = g.key(old), g.key(new)
+
+	n, ok := g.nodes[old]
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrNotFound, old)
+	}
+
+	if old == new {
+		return nil
+	}
+
+	if _, ok := g.nodes[new]; ok {
+		return fmt.Errorf("%w: %v", ErrExists, new)
+	}
+
+	delete(g.nodes, old)
+	g.nodes[new] = n
+
+	if g.indegree != nil {
+		g.indegree[new] = g.indegree[old]
+		delete(g.indegree, old)
+	}
+
+	// We update all edges to the node, including a self-loop.
+	for _, e := range g.nodes {
+		if e[old] {
+			delete(e, old)
+			e.add(new)
+		}
+	}
+
+	g.changed()
+
+	return nil
+}
+
+// Neighbors returns the nodes that are connected to a node by an edge in
+// either direction, without duplicates. The node itself is not included, even
+// if it has an edge to itself. It returns nil if the node does not exist.
+// Finding the nodes with an edge to the node requires a scan over all edges.
+func (g *Graph[Key]) Neighbors(key Key) []Key {
+	key = g.key(key)
+
+	n, ok := g.nodes[key]
+	if !ok {
+		return nil
+	}
+
+	var neighbors []Key
+	seen := make(map[Key]bool, len(n))
+
+	for to := range n {
+		if to != key && !seen[to] {
+			seen[to] = true
+			neighbors = append(neighbors, to)
+		}
+	}
+
+	for from, e := range g.nodes {
+		if from != key && e[key] && !seen[from] {
+			seen[from] = true
+			neighbors = append(neighbors, from)
+		}
+	}
+
+	return neighbors
+}
+
+// Reverse returns a new graph with all edges reversed. The new graph uses the
+// same key normalizer.
 func (g *Graph[Key]) Reverse() *Graph[Key] {
 	r := New[Key]()
+	r.normalize = g.normalize
 
 	for from, e := range g.nodes {
-		r.Node(from)
+		r.node(from)
 		for to := range e {
-			r.Edge(to, from)
+			r.node(to).add(from)
 		}
 	}
 
 	return r
 }
 
-// Copy returns a new graph with the same nodes and edges.
+// Copy returns a new graph with the same nodes and edges. The copy is fully
+// independent: every node gets new Edges, so adding or removing nodes and
+// edges in the copy never affects the graph, and vice versa. The new graph
+// uses the same key normalizer, is dirty if the graph is dirty, and has a
+// degree cache if the graph has one.
 func (g *Graph[Key]) Copy() *Graph[Key] {
 	c := New[Key]()
+	c.normalize = g.normalize
 
 	for from, e := range g.nodes {
-		c.Node(from)
+		n := c.node(from)
 		for to := range e {
-			c.Edge(from, to)
+			c.node(to)
+			n.add(to)
 		}
 	}
+	c.dirty = g.dirty
+	if g.indegree != nil {
+		c.indegree = maps.Clone(g.indegree)
+	}
 
 	return c
 }
 
-// Sort returns a topological sorted list of the graph nodes. It returns an
-// error if the graph has a cycle. It is an implementation of Kahn's algorithm.
-// Sort's time complexity is O(n) for n = [number of nodes] + [number of edges].
-func (g *Graph[Key]) Sort() ([]Key, error) {
-	// https://en.wikipedia.org/wiki/Topological_sorting#Kahn's_algorithm
+// Compact rebuilds the internal maps of the graph at their current size. Go
+// maps do not shrink when entries are deleted, so a graph that was much larger
+// before nodes or edges were removed keeps using the memory of its largest
+// size. Compact releases that memory. It takes O(n) time for n = [number of
+// nodes] + [number of edges], so it is only worth calling after removing a
+// large part of the graph, for example in a long-lived process.
+//
+// Edges returned by Node before calling Compact are no longer part of the
+// graph afterwards, so they must not be used to modify it.
+func (g *Graph[Key]) Compact() {
+	nodes := make(map[Key]Edges[Key], len(g.nodes))
+
+	for from, e := range g.nodes {
+		n := make(Edges[Key], len(e))
+		for to := range e {
+			n.add(to)
+		}
+		nodes[from] = n
+	}
+
+	g.nodes = nodes
+
+	// The degree cache and the order kept by EdgeChecked have an entry for
+	// every node too.
+	if g.indegree != nil {
+		indegree := make(map[Key]int, len(g.indegree))
+		maps.Copy(indegree, g.indegree)
+		g.indegree = indegree
+	}
+	if g.pos != nil {
+		pos := make(map[Key]int, len(g.pos))
+		maps.Copy(pos, g.pos)
+		g.pos = pos
+	}
+}
 
-	// We need to make a copy of the graph, so we can modify it.
-	gg := g.Copy()
+// subgraph returns a new graph with the given nodes of the graph, and the edges
+// between them. The new graph uses the same key normalizer.
+func (g *Graph[Key]) subgraph(keep map[Key]bool) *Graph[Key] {
+	s := New[Key]()
+	s.normalize = g.normalize
 
-	// The original graph's edges are actually outgoing edges. We need to
-	// reverse the graph to detect nodes with no incoming edges in an efficient
-	// way. Without reversing the graph, we would need to iterate over all
-	// nodes and their edges to find nodes with no incoming edges.
-	r := g.Reverse()
+	for from := range keep {
+		s.Node(from)
+		for to := range g.nodes[from] {
+			if keep[to] {
+				s.Edge(from, to)
+			}
+		}
+	}
 
+	return s
+}
+
+// Sort returns a topological sorted list of the graph nodes. It returns
+// ErrCycle if the graph has a cycle. It is an implementation of Kahn's
+// algorithm. Sort's time complexity is O(n) for n = [number of nodes] +
+// [number of edges].
+func (g *Graph[Key]) Sort() ([]Key, error) {
 	// The sorted list of keys, which we will return
 	var sorted []Key
 
+	if !g.kahn(func(n Key) bool {
+		sorted = append(sorted, n)
+		return true
+	}) {
+		return nil, ErrCycle
+	}
+
+	return sorted, nil
+}
+
+// kahn visits the nodes of the graph in topological order, calling emit for
+// each node until emit returns false. It returns true if all nodes have been
+// emitted. It returns false if emit returned false, or if the graph has a
+// cycle, in which case emit has been called for the nodes that could be
+// ordered before the cycle was found.
+func (g *Graph[Key]) kahn(emit func(Key) bool) bool {
+	// https://en.wikipedia.org/wiki/Topological_sorting#Kahn's_algorithm
+
+	// The original graph's edges are outgoing edges. Instead of removing
+	// edges from a copy of the graph, we count the incoming edges of every
+	// node, and decrease the count when we visit the node the edge comes
+	// from. This only needs a single number per node.
+	indegree := g.InDegrees()
+
 	// The list of keys with no incoming edges. We need this to start the
-	// algorithm. We construct it using the reversed graph and finding nodes
-	// with no outgoing edges.
+	// algorithm.
 	var next []Key
-	for k, e := range r.nodes {
-		if len(e) == 0 {
+	for k := range g.nodes {
+		if indegree[k] == 0 {
 			next = append(next, k)
 		}
 	}
 
+	// The number of nodes we emitted, which we need to detect cycles.
+	emitted := 0
+
 	// We iterate over the list of nodes with no incoming edges. This list will
 	// be empty when the graph is empty or when the graph has a cycle.
 	for len(next) > 0 {
 		n := next[0]
 		next = next[1:]
 
-		// We add the node n to the sorted list.
-		sorted = append(sorted, n)
+		// We emit the node n, as all nodes before it have been emitted.
+		if !emit(n) {
+			return false
+		}
+		emitted++
 
 		// We iterate over the nodes that are connected to the current node n.
 		// We only consider outgoing edges, because the node we are visiting
-		// has no incoming edges.
-		for m := range gg.nodes[n] {
-			// We remove the edge from n to m from the graph.
-			delete(gg.nodes[n], m)
-			delete(r.nodes[m], n)
-
-			// If the node m has no incoming edges left after we removed the
-			// edge from n to m, we add it to the list of nodes with no
-			// incoming edges, so we can consider it in the next iteration.
-			if len(r.nodes[m]) == 0 {
+		// has no incoming edges left.
+		for m := range g.nodes[n] {
+			// We remove the edge from n to m by decreasing the number of
+			// incoming edges of m. If the node m has no incoming edges left,
+			// we add it to the list of nodes with no incoming edges, so we can
+			// consider it in the next iteration.
+			indegree[m]--
+			if indegree[m] == 0 {
 				next = append(next, m)
 			}
 		}
-
-		// We remove the node n from the graph. This is necessary to detect
-		// cycles.
-		delete(gg.nodes, n)
-		delete(r.nodes, n)
 	}
 
-	// If the graph is not empty, it means that there is a cycle in the graph.
-	if len(gg.nodes) > 0 {
-		return nil, fmt.Errorf("cycle detected")
-	}
-
-	return sorted, nil
+	// If not all nodes have been emitted, the remaining nodes have incoming
+	// edges left, which means that there is a cycle in the graph.
+	return emitted == len(g.nodes)
 }
 
 // add adds a key to the edges.
@@ -3,7 +3,11 @@
 package graph
 
 import (
+	"errors"
+	"maps"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -83,6 +87,432 @@ func TestComplexSort(t *testing.T) {
 	}
 }
 
+func TestLookup(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with a single edge a -> b.
+	g.Edge("a", "b")
+
+	if e, ok := g.Lookup("a"); !ok || !reflect.DeepEqual(e, Edges[string]{"b": true}) {
+		t.Errorf("expected [b] and true, got %v and %v", e, ok)
+	}
+
+	if e, ok := g.Lookup("c"); ok || e != nil {
+		t.Errorf("expected nil and false, got %v and %v", e, ok)
+	}
+
+	// Looking up a node must not create it.
+	if len(g.nodes) != 2 {
+		t.Errorf("expected 2 nodes, got %v", len(g.nodes))
+	}
+}
+
+func TestEdgeDirection(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, b -> c and
+	// c -> b.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "b")
+
+	for _, c := range []struct {
+		a, b       string
+		aToB, bToA bool
+	}{
+		{"a", "b", true, false},
+		{"b", "a", false, true},
+		{"b", "c", true, true},
+		{"a", "c", false, false},
+		{"a", "x", false, false},
+	} {
+		if aToB, bToA := g.EdgeDirection(c.a, c.b); aToB != c.aToB || bToA != c.bToA {
+			t.Errorf("expected %v and %v for %v and %v, got %v and %v", c.aToB, c.bToA, c.a, c.b, aToB, bToA)
+		}
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, c -> b,
+	// b -> d, b -> b and d -> b. The neighbors of b are a, c and d, and d must
+	// only be reported once. The self-loop on b must be ignored.
+	g.Edge("a", "b")
+	g.Edge("c", "b")
+	g.Edge("b", "d")
+	g.Edge("b", "b")
+	g.Edge("d", "b")
+
+	neighbors := g.Neighbors("b")
+	slices.Sort(neighbors)
+
+	if !reflect.DeepEqual(neighbors, []string{"a", "c", "d"}) {
+		t.Errorf("expected [a c d], got %v", neighbors)
+	}
+
+	if neighbors := g.Neighbors("x"); neighbors != nil {
+		t.Errorf("expected nil, got %v", neighbors)
+	}
+
+	if _, ok := g.nodes["x"]; ok {
+		t.Error("expected Neighbors not to create the node")
+	}
+}
+
+func TestRename(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c,
+	// b -> b. Renaming b to x must keep all edges, including the self-loop.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("b", "b")
+
+	if err := g.Rename("b", "x"); err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := map[string]Edges[string]{
+		"a": {"x": true},
+		"x": {"c": true, "x": true},
+		"c": {},
+	}
+
+	if !reflect.DeepEqual(g.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, g.nodes)
+	}
+
+	if err := g.Rename("b", "y"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := g.Rename("a", "c"); !errors.Is(err, ErrExists) {
+		t.Errorf("expected ErrExists, got %v", err)
+	}
+
+	if !reflect.DeepEqual(g.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, g.nodes)
+	}
+}
+
+func TestKeyNormalizer(t *testing.T) {
+	g := New[string]()
+
+	// We add a node before setting the normalizer, to check that existing
+	// nodes are normalized and merged.
+	g.Edge("A", "b")
+	g.Edge("a", "c")
+
+	g.SetKeyNormalizer(strings.ToLower)
+
+	g.Add("Foo", []string{"B"})
+	g.Edge("foo", "BAR")
+
+	expected := map[string]Edges[string]{
+		"a":   {"b": true, "c": true},
+		"b":   {},
+		"c":   {},
+		"foo": {"b": true, "bar": true},
+		"bar": {},
+	}
+
+	if !reflect.DeepEqual(g.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, g.nodes)
+	}
+
+	if !g.HasEdge("FOO", "Bar") {
+		t.Error("expected edge FOO -> Bar")
+	}
+
+	if g.HasEdge("bar", "foo") {
+		t.Error("expected no edge bar -> foo")
+	}
+
+	// The copy uses the same normalizer.
+	c := g.Copy()
+	c.Edge("BAR", "A")
+
+	if !c.HasEdge("bar", "a") {
+		t.Error("expected edge bar -> a in copy")
+	}
+}
+
+func TestCopy(t *testing.T) {
+	g := New[string]()
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+
+	c := g.Copy()
+
+	if !reflect.DeepEqual(c.nodes, g.nodes) {
+		t.Errorf("expected %v, got %v", g.nodes, c.nodes)
+	}
+
+	// Changing the copy, including through the Edges returned by Node, must
+	// not change the graph.
+	c.Edge("a", "c")
+	c.Node("x")
+	delete(c.Node("b"), "c")
+	_ = c.Rename("a", "y")
+
+	expected := map[string]Edges[string]{
+		"a": {"b": true},
+		"b": {"c": true},
+		"c": {},
+	}
+
+	if !reflect.DeepEqual(g.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, g.nodes)
+	}
+
+	// And the other way around.
+	g.Edge("c", "a")
+
+	if c.HasEdge("c", "a") {
+		t.Error("expected the copy not to change")
+	}
+}
+
+func TestCompact(t *testing.T) {
+	g := buildGraph(1000)
+	expected := g.Copy()
+
+	g.Compact()
+
+	if !reflect.DeepEqual(g.nodes, expected.nodes) {
+		t.Error("expected the same nodes and edges after compacting")
+	}
+
+	// The degree cache and the order kept by EdgeChecked are compacted too,
+	// and stay valid.
+	g.EnableDegreeCache()
+	if err := g.EdgeChecked(999, 0); err != nil {
+		t.Error(err)
+		return
+	}
+	order, pos := slices.Clone(g.order), maps.Clone(g.pos)
+
+	g.Compact()
+
+	uncached := g.Copy()
+	uncached.indegree = nil
+
+	if !reflect.DeepEqual(g.InDegrees(), uncached.InDegrees()) {
+		t.Error("expected the same in-degrees after compacting")
+	}
+
+	if !reflect.DeepEqual(g.order, order) || !reflect.DeepEqual(g.pos, pos) {
+		t.Error("expected the same order after compacting")
+	}
+}
+
+func TestRemoveMatching(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b.gen -> c,
+	// a -> c, d.gen -> a. Removing the generated nodes leaves a -> c.
+	g.Edge("a", "b.gen")
+	g.Edge("b.gen", "c")
+	g.Edge("a", "c")
+	g.Edge("d.gen", "a")
+
+	n := g.RemoveMatching(func(k string) bool {
+		return strings.HasSuffix(k, ".gen")
+	})
+
+	if n != 2 {
+		t.Errorf("expected 2, got %v", n)
+	}
+
+	expected := map[string]Edges[string]{
+		"a": {"c": true},
+		"c": {},
+	}
+
+	if !reflect.DeepEqual(g.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, g.nodes)
+	}
+}
+
+func TestUndeclaredNodes(t *testing.T) {
+	g := New[string]()
+
+	// We declare a and b, where b references c and a references d.
+	g.Add("a", []string{"d"})
+	g.Add("b", []string{"c", "a"})
+
+	undeclared := g.UndeclaredNodes([]string{"a", "b"})
+
+	if !reflect.DeepEqual(undeclared, []string{"c", "d"}) {
+		t.Errorf("expected [c d], got %v", undeclared)
+	}
+
+	if undeclared := g.UndeclaredNodes([]string{"a", "b", "c", "d"}); len(undeclared) != 0 {
+		t.Errorf("expected no nodes, got %v", undeclared)
+	}
+}
+
+func TestFromRequires(t *testing.T) {
+	// We construct a graph with the following structure: a -> b, a -> c,
+	// b -> c, and d without dependencies. The dependency c is not a key of
+	// the map, but it is still a node.
+	g := FromRequires(map[string][]string{
+		"a": {"b", "c"},
+		"b": {"c"},
+		"d": nil,
+	})
+
+	expected := map[string]Edges[string]{
+		"a": {"b": true, "c": true},
+		"b": {"c": true},
+		"c": {},
+		"d": {},
+	}
+
+	if !reflect.DeepEqual(g.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, g.nodes)
+	}
+}
+
+func TestDegreeCache(t *testing.T) {
+	g := New[string]()
+	g.Edge("a", "b")
+	g.EnableDegreeCache()
+
+	// check compares the cached in-degrees with the in-degrees counted from
+	// the edges.
+	check := func(step string) {
+		t.Helper()
+		expected := (&Graph[string]{nodes: g.nodes}).InDegrees()
+		if !reflect.DeepEqual(g.indegree, expected) {
+			t.Errorf("expected %v after %v, got %v", expected, step, g.indegree)
+		}
+	}
+
+	check("enabling the cache")
+
+	g.Add("c", []string{"a", "b", "c"})
+	g.Edge("a", "b")
+	check("adding edges")
+
+	g.RemoveEdgeOrdered(nil, "c", "b")
+	check("removing an edge")
+
+	if err := g.Rename("b", "x"); err != nil {
+		t.Error(err)
+		return
+	}
+	check("renaming a node")
+
+	g.RemoveMatching(func(k string) bool { return k == "a" })
+	check("removing a node")
+
+	g.Edge("X", "c")
+	g.SetKeyNormalizer(strings.ToLower)
+	check("normalizing keys")
+
+	c := g.Copy()
+	c.Edge("y", "x")
+	check("changing a copy")
+
+	if _, err := g.Sort(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle for the self-loop, got %v", err)
+	}
+
+	g.RemoveEdgeOrdered(nil, "c", "c")
+	check("removing a self-loop")
+
+	keys, err := g.Sort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	checkOrder(t, g, keys)
+}
+
+func TestValidate(t *testing.T) {
+	g, err := Validate([]string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}})
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := map[string]Edges[string]{
+		"a": {"b": true},
+		"b": {"c": true},
+		"c": {},
+	}
+
+	if !reflect.DeepEqual(g.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, g.nodes)
+	}
+
+	if _, err := Validate([]string{"a", "b"}, [][2]string{{"a", "b"}, {"b", "c"}}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if _, err := Validate([]string{"a", "b", "a"}, nil); !errors.Is(err, ErrExists) {
+		t.Errorf("expected ErrExists, got %v", err)
+	}
+}
+
+func TestAddSuperSinkAndSource(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, a -> c and
+	// an isolated node d.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Node("d")
+
+	if k := g.AddSuperSink("t"); k != "t" {
+		t.Errorf("expected t, got %v", k)
+	}
+
+	if k := g.AddSuperSource("s"); k != "s" {
+		t.Errorf("expected s, got %v", k)
+	}
+
+	expected := map[string]Edges[string]{
+		"s": {"a": true, "d": true},
+		"a": {"b": true, "c": true},
+		"b": {"t": true},
+		"c": {"t": true},
+		"d": {"t": true},
+		"t": {},
+	}
+
+	if !reflect.DeepEqual(g.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, g.nodes)
+	}
+
+	if source, sink, ok := g.IsSourceSink(); !ok || source != "s" || sink != "t" {
+		t.Errorf("expected s and t, got %v, %v and %v", source, sink, ok)
+	}
+}
+
+func TestDanglingEdges(t *testing.T) {
+	g := New[string]()
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+
+	if edges := g.DanglingEdges(); edges != nil {
+		t.Errorf("expected nil, got %v", edges)
+	}
+
+	// Modifying the edges of a node directly does not create the target.
+	g.Node("b")["x"] = true
+	g.Node("a")["y"] = true
+
+	expected := [][2]string{{"a", "y"}, {"b", "x"}}
+	if edges := g.DanglingEdges(); !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected %v, got %v", expected, edges)
+	}
+}
+
 // buildGraph creates a graph with a specific structure. It is used to benchmark
 // the topological sort algorithm.
 // It generates a directed graph with n int nodes, and 2n edges. The graph is
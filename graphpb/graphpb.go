@@ -0,0 +1,218 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+// Package graphpb converts graphs with string keys to and from the Protocol
+// Buffers messages defined in graph.proto, for exchanging graphs with programs
+// in other languages.
+//
+// The messages are written by hand and encode themselves in the protobuf wire
+// format, so this package does not depend on a protobuf runtime. Other
+// languages can generate code from graph.proto and read the encoded messages
+// directly.
+//
+//	b, err := graphpb.FromGraph(g).Marshal()
+//
+//	var m graphpb.Graph
+//	err := m.Unmarshal(b)
+//	g := m.ToGraph()
+package graphpb
+
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+
+	"github.com/amicolabs/graph"
+)
+
+// errInvalid is returned when unmarshaling data that is not a valid message.
+var errInvalid = errors.New("graphpb: invalid message")
+
+// Graph is a directed graph with string keys. See graph.proto.
+type Graph struct {
+	// The keys of all nodes, including nodes without edges.
+	Nodes []string
+
+	// The edges of the graph.
+	Edges []*Edge
+}
+
+// Edge is a directed edge between two nodes. See graph.proto.
+type Edge struct {
+	From string
+	To   string
+}
+
+// FromGraph returns the message for a graph. Nodes and edges are sorted, so
+// equal graphs result in equal messages.
+func FromGraph(g *graph.Graph[string]) *Graph {
+	// OutDegrees lists every node in a single pass, without sorting the graph
+	// topologically like Index.
+	degrees := g.OutDegrees()
+	nodes := make([]string, 0, len(degrees))
+	for k := range degrees {
+		nodes = append(nodes, k)
+	}
+	slices.Sort(nodes)
+
+	m := &Graph{Nodes: nodes}
+	for _, from := range nodes {
+		edges, _ := g.Lookup(from)
+
+		var to []string
+		for t := range edges {
+			to = append(to, t)
+		}
+		slices.Sort(to)
+
+		for _, t := range to {
+			m.Edges = append(m.Edges, &Edge{From: from, To: t})
+		}
+	}
+
+	return m
+}
+
+// ToGraph returns the graph for a message. Nodes that are only referenced by
+// edges are created too.
+func (m *Graph) ToGraph() *graph.Graph[string] {
+	g := graph.New[string]()
+
+	for _, n := range m.Nodes {
+		g.Node(n)
+	}
+	for _, e := range m.Edges {
+		g.Edge(e.From, e.To)
+	}
+
+	return g
+}
+
+// Marshal encodes the message in the protobuf wire format.
+func (m *Graph) Marshal() ([]byte, error) {
+	var b []byte
+
+	for _, n := range m.Nodes {
+		b = appendString(b, 1, n)
+	}
+	for _, e := range m.Edges {
+		b = appendBytes(b, 2, e.marshal())
+	}
+
+	return b, nil
+}
+
+// Unmarshal decodes a message in the protobuf wire format. Unknown fields are
+// skipped.
+func (m *Graph) Unmarshal(b []byte) error {
+	*m = Graph{}
+
+	return fields(b, func(field int, value []byte) error {
+		switch field {
+		case 1:
+			m.Nodes = append(m.Nodes, string(value))
+		case 2:
+			e := &Edge{}
+			if err := e.unmarshal(value); err != nil {
+				return err
+			}
+			m.Edges = append(m.Edges, e)
+		}
+		return nil
+	})
+}
+
+// marshal encodes the edge in the protobuf wire format. Empty strings are
+// omitted, as they are the default value.
+func (e *Edge) marshal() []byte {
+	var b []byte
+	if e.From != "" {
+		b = appendString(b, 1, e.From)
+	}
+	if e.To != "" {
+		b = appendString(b, 2, e.To)
+	}
+	return b
+}
+
+// unmarshal decodes an edge in the protobuf wire format.
+func (e *Edge) unmarshal(b []byte) error {
+	return fields(b, func(field int, value []byte) error {
+		switch field {
+		case 1:
+			e.From = string(value)
+		case 2:
+			e.To = string(value)
+		}
+		return nil
+	})
+}
+
+// The protobuf wire types.
+const (
+	wireVarint = 0
+	wireI64    = 1
+	wireLen    = 2
+	wireI32    = 5
+)
+
+// appendString appends a string field to b.
+func appendString(b []byte, field int, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireLen)
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// appendBytes appends a length-delimited field to b.
+func appendBytes(b []byte, field int, v []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireLen)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// fields calls fn for every length-delimited field in b, and skips fields of
+// other wire types, as the messages only have length-delimited fields.
+func fields(b []byte, fn func(field int, value []byte) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 || tag>>3 == 0 {
+			return errInvalid
+		}
+		b = b[n:]
+
+		switch tag & 7 {
+		case wireVarint:
+			_, n := binary.Uvarint(b)
+			if n <= 0 {
+				return errInvalid
+			}
+			b = b[n:]
+
+		case wireI64, wireI32:
+			size := 8
+			if tag&7 == wireI32 {
+				size = 4
+			}
+			if len(b) < size {
+				return errInvalid
+			}
+			b = b[size:]
+
+		case wireLen:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return errInvalid
+			}
+			value := b[n : n+int(l)]
+			b = b[n+int(l):]
+
+			if err := fn(int(tag>>3), value); err != nil {
+				return err
+			}
+
+		default:
+			return errInvalid
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graphpb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/amicolabs/graph"
+)
+
+func TestRoundTrip(t *testing.T) {
+	g := graph.New[string]()
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("a", "c")
+	g.Node("d")
+
+	b, err := FromGraph(g).Marshal()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var m Graph
+	if err := m.Unmarshal(b); err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := &Graph{
+		Nodes: []string{"a", "b", "c", "d"},
+		Edges: []*Edge{{"a", "b"}, {"a", "c"}, {"b", "c"}},
+	}
+
+	if !reflect.DeepEqual(&m, expected) {
+		t.Errorf("expected %v, got %v", expected, m)
+	}
+
+	// Converting the message back gives an equal graph, and so an equal
+	// message.
+	again, _ := FromGraph(m.ToGraph()).Marshal()
+
+	if !bytes.Equal(b, again) {
+		t.Errorf("expected %x, got %x", b, again)
+	}
+}
+
+func TestWireFormat(t *testing.T) {
+	m := &Graph{
+		Nodes: []string{"a"},
+		Edges: []*Edge{{"a", "b"}},
+	}
+
+	b, _ := m.Marshal()
+
+	// These are the bytes protoc generated code produces for the message:
+	// field 1 "a", and field 2 with an embedded message with field 1 "a" and
+	// field 2 "b".
+	expected := []byte{0x0a, 0x01, 'a', 0x12, 0x06, 0x0a, 0x01, 'a', 0x12, 0x01, 'b'}
+
+	if !bytes.Equal(b, expected) {
+		t.Errorf("expected %x, got %x", expected, b)
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	var m Graph
+
+	for _, b := range [][]byte{
+		{0x0a, 0x05, 'a'},
+		{0x0a},
+		{0x00},
+		{0x12, 0x02, 0x0a, 0x05},
+	} {
+		if err := m.Unmarshal(b); err == nil {
+			t.Errorf("expected error for %x", b)
+		}
+	}
+}
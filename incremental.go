@@ -0,0 +1,239 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import "slices"
+
+// AddEdgeOrdered adds an edge to the graph and repairs a topological order of
+// the graph, so it is a valid topological order of the graph including the new
+// edge. The order must be a valid topological order of the graph before the
+// edge is added; nodes that do not exist yet are appended to it. The order is
+// updated in place and returned, like append does.
+//
+// It returns ErrCycle if the new edge would create a cycle, in which case the
+// edge is not added and the order is not changed.
+//
+// AddEdgeOrdered uses the incremental topological order algorithm of Pearce
+// and Kelly. Only the nodes between the positions of the endpoints of the edge
+// are visited, which is usually much less work than sorting the whole graph
+// again.
+func (g *Graph[Key]) AddEdgeOrdered(order []Key, from, to Key) ([]Key, error) {
+	from, to = g.key(from), g.key(to)
+
+	if from == to {
+		return order, ErrCycle
+	}
+
+	pos := make(map[Key]int, len(order))
+	for i, k := range order {
+		pos[k] = i
+	}
+
+	// Nodes that are not part of the order yet have no edges, so they can be
+	// placed anywhere. We place them at the end.
+	for _, k := range []Key{from, to} {
+		if _, ok := pos[k]; !ok {
+			pos[k] = len(order)
+			order = append(order, k)
+		}
+	}
+
+	if !g.reorder(order, pos, from, to) {
+		return order, ErrCycle
+	}
+
+	g.Edge(from, to)
+
+	return order, nil
+}
+
+// RemoveEdgeOrdered removes an edge from the graph, and returns the topological
+// order of the graph after the removal. Removing an edge never invalidates a
+// topological order, so the order is returned unchanged; it exists as the
+// counterpart of AddEdgeOrdered. It does nothing if the edge does not exist.
+func (g *Graph[Key]) RemoveEdgeOrdered(order []Key, from, to Key) []Key {
+	g.unlink(g.key(from), g.key(to))
+
+	return order
+}
+
+// EdgeChecked adds an edge to the graph, unless it would create a cycle, in
+// which case it returns ErrCycle and leaves the graph unchanged. It creates the
+// nodes if they do not exist. It also returns ErrCycle if the graph already has
+// a cycle.
+//
+// The graph keeps a topological order between calls, which EdgeChecked repairs
+// for every new edge like AddEdgeOrdered does, so a stream of edges can be
+// checked without searching the whole graph for every edge. Any other change
+// to the graph discards the order, so the next call sorts the graph again.
+func (g *Graph[Key]) EdgeChecked(from, to Key) error {
+	from, to = g.key(from), g.key(to)
+
+	if from == to {
+		return ErrCycle
+	}
+
+	if g.pos == nil {
+		order, err := g.Sort()
+		if err != nil {
+			return err
+		}
+
+		g.order = order
+		g.pos = make(map[Key]int, len(order))
+		for i, k := range order {
+			g.pos[k] = i
+		}
+	}
+
+	if g.nodes[from][to] {
+		return nil
+	}
+
+	// A new node has no edges yet, so an edge to or from it cannot close a
+	// cycle. New nodes are placed at the end of the order.
+	order, pos := g.order, g.pos
+	for _, k := range []Key{from, to} {
+		if _, ok := pos[k]; !ok {
+			pos[k] = len(order)
+			order = append(order, k)
+		}
+	}
+
+	if !g.reorder(order, pos, from, to) {
+		return ErrCycle
+	}
+
+	g.node(from)
+	g.node(to)
+	g.link(from, to)
+
+	// Changing the graph discarded the order, but it is valid for the graph
+	// with the new edge.
+	g.order, g.pos = order, pos
+
+	return nil
+}
+
+// reorder updates the topological order and the positions of its nodes for a
+// new edge from -> to, that has not been added to the graph yet. It returns
+// false if the edge would create a cycle, in which case the order is not
+// changed.
+func (g *Graph[Key]) reorder(order []Key, pos map[Key]int, from, to Key) bool {
+	// https://doi.org/10.1145/1187436.1210590
+
+	lower, upper := pos[to], pos[from]
+
+	// If the edge points forward in the order, the order is still valid.
+	if lower > upper {
+		return true
+	}
+
+	// We search forward from to, visiting only nodes up to the position of
+	// from. If we find from, the new edge closes a cycle.
+	var forward []Key
+	visited := map[Key]bool{to: true}
+	stack := []Key{to}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		forward = append(forward, n)
+
+		for m := range g.nodes[n] {
+			if m == from {
+				return false
+			}
+			if !visited[m] && pos[m] < upper {
+				visited[m] = true
+				stack = append(stack, m)
+			}
+		}
+	}
+
+	// We search backward from from, visiting only nodes from the position of
+	// to. The graph only has outgoing edges, so we collect the incoming edges
+	// of the affected part of the order first.
+	incoming := make(map[Key][]Key)
+	for _, n := range order[lower : upper+1] {
+		for m := range g.nodes[n] {
+			incoming[m] = append(incoming[m], n)
+		}
+	}
+
+	var backward []Key
+	visited[from] = true
+	stack = append(stack, from)
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		backward = append(backward, n)
+
+		for _, m := range incoming[n] {
+			if !visited[m] && pos[m] > lower {
+				visited[m] = true
+				stack = append(stack, m)
+			}
+		}
+	}
+
+	// The nodes we found backward must come before the nodes we found
+	// forward. Within both sets we keep the existing relative order, and we
+	// reuse the positions the nodes had.
+	byPos := func(a, b Key) int {
+		return pos[a] - pos[b]
+	}
+	slices.SortFunc(forward, byPos)
+	slices.SortFunc(backward, byPos)
+
+	nodes := append(backward, forward...)
+	positions := make([]int, len(nodes))
+	for i, n := range nodes {
+		positions[i] = pos[n]
+	}
+	slices.Sort(positions)
+
+	for i, n := range nodes {
+		order[positions[i]] = n
+		pos[n] = positions[i]
+	}
+
+	return true
+}
+
+// CanAddEdges reports whether adding all edges at once would keep the graph
+// free of new cycles, without changing the graph. If the edges would create
+// cycles, it returns false and the edges that are part of them, in a
+// deterministic order. Edges that already exist, and cycles that already
+// exist, are ignored. Adding each edge separately may be fine while adding
+// all of them is not, so this checks the edges together.
+func (g *Graph[Key]) CanAddEdges(edges [][2]Key) (bool, [][2]Key) {
+	c := g.Copy()
+
+	var added [][2]Key
+	for _, e := range edges {
+		from, to := g.key(e[0]), g.key(e[1])
+		if !c.nodes[from][to] {
+			c.Edge(from, to)
+			added = append(added, [2]Key{from, to})
+		}
+	}
+
+	// An edge is part of a cycle if both its nodes are in the same strongly
+	// connected component.
+	_, id := c.condensation()
+
+	var cyclic [][2]Key
+	for _, e := range added {
+		if id[e[0]] == id[e[1]] {
+			cyclic = append(cyclic, e)
+		}
+	}
+
+	if len(cyclic) == 0 {
+		return true, nil
+	}
+
+	sortEdges(cyclic)
+
+	return false, cyclic
+}
@@ -0,0 +1,213 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// checkOrder reports an error if order is not a topological order of g.
+func checkOrder[Key comparable](t *testing.T, g *Graph[Key], order []Key) {
+	t.Helper()
+
+	pos := make(map[Key]int, len(order))
+	for i, k := range order {
+		pos[k] = i
+	}
+
+	if len(pos) != len(order) || len(order) != len(g.nodes) {
+		t.Errorf("expected every node exactly once, got %v", order)
+		return
+	}
+
+	for from, e := range g.nodes {
+		for to := range e {
+			if pos[from] >= pos[to] {
+				t.Errorf("expected %v before %v, got %v", from, to, order)
+				return
+			}
+		}
+	}
+}
+
+func TestAddEdgeOrdered(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, c -> d.
+	// The order [c d a b] is valid. Adding b -> c forces a and b before c and
+	// d.
+	g.Edge("a", "b")
+	g.Edge("c", "d")
+
+	order, err := g.AddEdgeOrdered([]string{"c", "d", "a", "b"}, "b", "c")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	checkOrder(t, g, order)
+
+	// Adding d -> a closes the cycle a -> b -> c -> d -> a.
+	_, err = g.AddEdgeOrdered(order, "d", "a")
+
+	if !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+
+	if g.nodes["d"]["a"] {
+		t.Error("expected edge d -> a not to be added")
+	}
+
+	checkOrder(t, g, order)
+}
+
+func TestAddEdgeOrderedRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	g := New[int]()
+
+	// We add random edges between 50 nodes one at a time, and check that the
+	// order stays valid and that cycles are detected exactly when Sort would
+	// detect them.
+	var order []int
+	for i := 0; i < 500; i++ {
+		from, to := r.Intn(50), r.Intn(50)
+
+		c := g.Copy()
+		c.Edge(from, to)
+		_, sortErr := c.Sort()
+
+		var err error
+		order, err = g.AddEdgeOrdered(order, from, to)
+
+		if (err == nil) != (sortErr == nil) {
+			t.Errorf("expected error %v for %v -> %v, got %v", sortErr, from, to, err)
+			return
+		}
+
+		checkOrder(t, g, order)
+	}
+}
+
+func TestRemoveEdgeOrdered(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.MarkClean()
+
+	order := g.RemoveEdgeOrdered([]string{"a", "b", "c"}, "a", "b")
+
+	if g.HasEdge("a", "b") || !g.Dirty() {
+		t.Error("expected a -> b to be removed")
+	}
+
+	checkOrder(t, g, order)
+
+	// Removing an edge that does not exist does not change the graph.
+	g.MarkClean()
+	g.RemoveEdgeOrdered(order, "c", "a")
+
+	if g.Dirty() {
+		t.Error("expected the graph to be unchanged")
+	}
+}
+
+func TestEdgeChecked(t *testing.T) {
+	g := New[string]()
+
+	// We stream the edges a -> b, b -> c, d -> a and c -> d. The last edge
+	// closes the cycle a -> b -> c -> d -> a, so it is rejected.
+	for _, e := range [][2]string{{"a", "b"}, {"b", "c"}, {"d", "a"}} {
+		if err := g.EdgeChecked(e[0], e[1]); err != nil {
+			t.Errorf("expected no error for %v, got %v", e, err)
+		}
+		checkOrder(t, g, g.order)
+	}
+
+	if err := g.EdgeChecked("c", "d"); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+
+	if g.HasEdge("c", "d") {
+		t.Error("expected c -> d to be rolled back")
+	}
+
+	if err := g.EdgeChecked("e", "e"); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+
+	// Other changes discard the order, which is then sorted again.
+	g.Edge("c", "e")
+
+	if g.pos != nil {
+		t.Error("expected the order to be discarded")
+	}
+
+	if err := g.EdgeChecked("d", "e"); err != nil {
+		t.Error(err)
+	}
+	checkOrder(t, g, g.order)
+}
+
+func TestEdgeCheckedRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	g := New[int]()
+
+	// We stream random edges, and check every accepted edge keeps the graph
+	// acyclic and every rejected edge would have closed a cycle.
+	for i := 0; i < 2000; i++ {
+		from, to := r.Intn(100), r.Intn(100)
+		exists := g.HasEdge(from, to)
+
+		err := g.EdgeChecked(from, to)
+
+		if err == nil {
+			continue
+		}
+
+		if exists || !errors.Is(err, ErrCycle) {
+			t.Errorf("expected ErrCycle for a new edge, got %v", err)
+			return
+		}
+
+		c := g.Copy()
+		c.Edge(from, to)
+		if _, err := c.Sort(); err == nil {
+			t.Errorf("expected %v -> %v to close a cycle", from, to)
+			return
+		}
+	}
+
+	checkOrder(t, g, g.order)
+}
+
+func TestCanAddEdges(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b and c -> d.
+	// Adding b -> c or d -> a is fine on its own, but adding both creates
+	// the cycle a -> b -> c -> d -> a.
+	g.Edge("a", "b")
+	g.Edge("c", "d")
+
+	if ok, cyclic := g.CanAddEdges([][2]string{{"b", "c"}, {"a", "b"}}); !ok || cyclic != nil {
+		t.Errorf("expected true and nil, got %v and %v", ok, cyclic)
+	}
+
+	ok, cyclic := g.CanAddEdges([][2]string{{"b", "c"}, {"d", "a"}, {"a", "e"}})
+	expected := [][2]string{{"b", "c"}, {"d", "a"}}
+
+	if ok || !reflect.DeepEqual(cyclic, expected) {
+		t.Errorf("expected false and %v, got %v and %v", expected, ok, cyclic)
+	}
+
+	// The graph must not be modified.
+	if len(g.nodes) != 4 || g.HasEdge("b", "c") {
+		t.Errorf("expected the graph to be unchanged, got %v", g.nodes)
+	}
+}
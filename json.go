@@ -0,0 +1,221 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// CytoscapeJSON writes the graph as JSON in the format of the elements of
+// Cytoscape.js:
+//
+//	{"elements": {
+//		"nodes": [{"data": {"id": "a"}}, {"data": {"id": "b"}}],
+//		"edges": [{"data": {"id": "a->b", "source": "a", "target": "b"}}]
+//	}}
+//
+// Node ids are the keys formatted with fmt's %v verb. Edge ids are the ids of
+// their source and target, joined by "->". In the ids of edges, every
+// backslash and every ">" of the source and target is escaped with a
+// backslash, so the only unescaped ">" is that of the separator, and
+// different edges always get different ids. Nodes and edges are written in a
+// deterministic order, so the output of equal graphs is equal.
+func (g *Graph[Key]) CytoscapeJSON(w io.Writer) error {
+	type data struct {
+		ID     string `json:"id"`
+		Source string `json:"source,omitempty"`
+		Target string `json:"target,omitempty"`
+	}
+
+	type element struct {
+		Data data `json:"data"`
+	}
+
+	var doc struct {
+		Elements struct {
+			Nodes []element `json:"nodes"`
+			Edges []element `json:"edges"`
+		} `json:"elements"`
+	}
+
+	doc.Elements.Nodes = []element{}
+	for _, k := range g.keys() {
+		doc.Elements.Nodes = append(doc.Elements.Nodes, element{data{ID: fmt.Sprint(k)}})
+	}
+
+	doc.Elements.Edges = []element{}
+	for _, e := range g.edges() {
+		source, target := fmt.Sprint(e[0]), fmt.Sprint(e[1])
+		doc.Elements.Edges = append(doc.Elements.Edges, element{data{
+			ID:     cytoscapeEscaper.Replace(source) + "->" + cytoscapeEscaper.Replace(target),
+			Source: source,
+			Target: target,
+		}})
+	}
+
+	return encodeJSON(w, doc)
+}
+
+// cytoscapeEscaper escapes the source and target in the ids of edges for
+// CytoscapeJSON.
+var cytoscapeEscaper = strings.NewReplacer(`\`, `\\`, ">", `\>`)
+
+// NodeLinkJSON writes the graph as JSON in the node-link format of NetworkX,
+// which networkx.node_link_graph reads:
+//
+//	{"directed": true, "multigraph": false, "graph": {},
+//		"nodes": [{"id": "a"}, {"id": "b"}],
+//		"links": [{"source": "a", "target": "b"}]}
+//
+// Node ids are the keys formatted with fmt's %v verb. Nodes and links are
+// written in a deterministic order, so the output of equal graphs is equal.
+func (g *Graph[Key]) NodeLinkJSON(w io.Writer) error {
+	type node struct {
+		ID string `json:"id"`
+	}
+
+	type link struct {
+		Source string `json:"source"`
+		Target string `json:"target"`
+	}
+
+	doc := struct {
+		Directed   bool           `json:"directed"`
+		Multigraph bool           `json:"multigraph"`
+		Graph      map[string]any `json:"graph"`
+		Nodes      []node         `json:"nodes"`
+		Links      []link         `json:"links"`
+	}{
+		Directed: true,
+		Graph:    map[string]any{},
+		Nodes:    []node{},
+		Links:    []link{},
+	}
+
+	for _, k := range g.keys() {
+		doc.Nodes = append(doc.Nodes, node{fmt.Sprint(k)})
+	}
+
+	for _, e := range g.edges() {
+		doc.Links = append(doc.Links, link{fmt.Sprint(e[0]), fmt.Sprint(e[1])})
+	}
+
+	return encodeJSON(w, doc)
+}
+
+// HierarchicalJSON writes the graph as JSON grouped by strongly connected
+// components, for rendering cycles as clusters that can be collapsed:
+//
+//	{"clusters": [
+//		{"id": 0, "nodes": ["a", "b"], "edges": [
+//			{"source": "a", "target": "b"}, {"source": "b", "target": "a"}]},
+//		{"id": 1, "nodes": ["c"], "edges": []}],
+//	 "edges": [{"source": "b", "target": "c", "sourceCluster": 0, "targetCluster": 1}]}
+//
+// Every cluster lists its nodes and the edges between them. The edges between
+// clusters are listed separately, with the clusters of both nodes. Clusters
+// are numbered in topological order, with ties broken by their first node, so
+// every edge between clusters goes to a cluster with a higher id. Node ids are
+// the keys formatted with fmt's %v verb, and nodes and edges are written in a
+// deterministic order, so the output of equal graphs is equal.
+func (g *Graph[Key]) HierarchicalJSON(w io.Writer) error {
+	type edge struct {
+		Source        string `json:"source"`
+		Target        string `json:"target"`
+		SourceCluster *int   `json:"sourceCluster,omitempty"`
+		TargetCluster *int   `json:"targetCluster,omitempty"`
+	}
+
+	type cluster struct {
+		ID    int      `json:"id"`
+		Nodes []string `json:"nodes"`
+		Edges []edge   `json:"edges"`
+	}
+
+	doc := struct {
+		Clusters []cluster `json:"clusters"`
+		Edges    []edge    `json:"edges"`
+	}{
+		Clusters: []cluster{},
+		Edges:    []edge{},
+	}
+
+	// The graph of components, with the first node of every component as its
+	// key, gives the components a deterministic topological order.
+	components, component := g.condensation()
+	first := make([]Key, len(components))
+	for i, c := range components {
+		first[i] = slices.MinFunc(c, compareKeys[Key])
+	}
+	condensed := g.GroupBy(func(k Key) Key { return first[component[k]] })
+	ids, _ := condensed.Index()
+
+	id := make(map[Key]int, len(g.nodes))
+	for k, c := range component {
+		id[k] = ids[first[c]]
+	}
+
+	for i := range components {
+		doc.Clusters = append(doc.Clusters, cluster{ID: i, Nodes: []string{}, Edges: []edge{}})
+	}
+	for _, k := range g.keys() {
+		c := &doc.Clusters[id[k]]
+		c.Nodes = append(c.Nodes, fmt.Sprint(k))
+	}
+
+	for _, e := range g.edges() {
+		f, t := id[e[0]], id[e[1]]
+		source, target := fmt.Sprint(e[0]), fmt.Sprint(e[1])
+		if f == t {
+			c := &doc.Clusters[f]
+			c.Edges = append(c.Edges, edge{Source: source, Target: target})
+		} else {
+			doc.Edges = append(doc.Edges, edge{Source: source, Target: target, SourceCluster: &f, TargetCluster: &t})
+		}
+	}
+
+	return encodeJSON(w, doc)
+}
+
+// AdjacencyList returns the edges of the graph as a map from every node to the
+// nodes it has an edge to, in a deterministic order. Nodes without outgoing
+// edges have an empty slice, so they are encoded as [] in JSON. The map is a
+// copy, so changing it does not change the graph.
+func (g *Graph[Key]) AdjacencyList() map[Key][]Key {
+	list := make(map[Key][]Key, len(g.nodes))
+	for from, e := range g.nodes {
+		to := make([]Key, 0, len(e))
+		for k := range e {
+			to = append(to, k)
+		}
+		sortKeys(to)
+		list[from] = to
+	}
+	return list
+}
+
+// EdgeColumns returns the edges of the graph as two parallel slices, where
+// there is an edge from from[i] to to[i], for storing edges in columns. The
+// edges are sorted by their source and then by their target, in the same
+// deterministic order as elsewhere. Nodes without edges are not included.
+func (g *Graph[Key]) EdgeColumns() (from []Key, to []Key) {
+	edges := g.edges()
+	from = make([]Key, len(edges))
+	to = make([]Key, len(edges))
+	for i, e := range edges {
+		from[i], to[i] = e[0], e[1]
+	}
+	return from, to
+}
+
+// encodeJSON writes v as JSON to w. It does not escape HTML characters, so
+// edge ids like "a->b" are written as is.
+func encodeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
@@ -0,0 +1,157 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCytoscapeJSON(t *testing.T) {
+	g := New[string]()
+	g.Edge("b", "c")
+	g.Edge("a", "b")
+	g.Node("d")
+
+	var buf bytes.Buffer
+	if err := g.CytoscapeJSON(&buf); err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := `{"elements":{"nodes":[{"data":{"id":"a"}},{"data":{"id":"b"}},{"data":{"id":"c"}},{"data":{"id":"d"}}],"edges":[{"data":{"id":"a->b","source":"a","target":"b"}},{"data":{"id":"b->c","source":"b","target":"c"}}]}}` + "\n"
+
+	if buf.String() != expected {
+		t.Errorf("expected %v, got %v", expected, buf.String())
+	}
+}
+
+func TestCytoscapeJSONEdgeIDs(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a->b -> c and
+	// a -> b->c. Joined by "->" without escaping, both edges would get the
+	// id a->b->c.
+	g.Edge("a->b", "c")
+	g.Edge("a", "b->c")
+
+	var buf bytes.Buffer
+	if err := g.CytoscapeJSON(&buf); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var doc struct {
+		Elements struct {
+			Edges []struct {
+				Data struct {
+					ID string `json:"id"`
+				} `json:"data"`
+			} `json:"edges"`
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var ids []string
+	for _, e := range doc.Elements.Edges {
+		ids = append(ids, e.Data.ID)
+	}
+
+	if expected := []string{`a->b-\>c`, `a-\>b->c`}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected %v, got %v", expected, ids)
+	}
+}
+
+func TestAdjacencyList(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> c, a -> b and
+	// b -> c.
+	g.Edge("a", "c")
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+
+	b, err := json.Marshal(g.AdjacencyList())
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := `{"a":["b","c"],"b":["c"],"c":[]}`
+	if string(b) != expected {
+		t.Errorf("expected %v, got %v", expected, string(b))
+	}
+}
+
+func TestNodeLinkJSON(t *testing.T) {
+	g := New[string]()
+	g.Edge("b", "c")
+	g.Edge("a", "b")
+	g.Node("d")
+
+	var b bytes.Buffer
+	if err := g.NodeLinkJSON(&b); err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := `{"directed":true,"multigraph":false,"graph":{},` +
+		`"nodes":[{"id":"a"},{"id":"b"},{"id":"c"},{"id":"d"}],` +
+		`"links":[{"source":"a","target":"b"},{"source":"b","target":"c"}]}` + "\n"
+
+	if b.String() != expected {
+		t.Errorf("expected %v, got %v", expected, b.String())
+	}
+}
+
+func TestEdgeColumns(t *testing.T) {
+	g := New[string]()
+	g.Edge("b", "c")
+	g.Edge("a", "c")
+	g.Edge("a", "b")
+	g.Node("d")
+
+	from, to := g.EdgeColumns()
+
+	if expected := []string{"a", "a", "b"}; !reflect.DeepEqual(from, expected) {
+		t.Errorf("expected %v, got %v", expected, from)
+	}
+	if expected := []string{"b", "c", "c"}; !reflect.DeepEqual(to, expected) {
+		t.Errorf("expected %v, got %v", expected, to)
+	}
+}
+
+func TestHierarchicalJSON(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> a,
+	// b -> c, x -> c and c -> c.
+	g.Edge("a", "b")
+	g.Edge("b", "a")
+	g.Edge("b", "c")
+	g.Edge("x", "c")
+	g.Edge("c", "c")
+
+	var buf bytes.Buffer
+	if err := g.HierarchicalJSON(&buf); err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := `{"clusters":[` +
+		`{"id":0,"nodes":["a","b"],"edges":[{"source":"a","target":"b"},{"source":"b","target":"a"}]},` +
+		`{"id":1,"nodes":["x"],"edges":[]},` +
+		`{"id":2,"nodes":["c"],"edges":[{"source":"c","target":"c"}]}],` +
+		`"edges":[{"source":"b","target":"c","sourceCluster":0,"targetCluster":2},` +
+		`{"source":"x","target":"c","sourceCluster":1,"targetCluster":2}]}` + "\n"
+
+	if buf.String() != expected {
+		t.Errorf("expected %v, got %v", expected, buf.String())
+	}
+}
@@ -0,0 +1,52 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLayout(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> y -> c and
+	// b -> x -> d. In the order of their keys, the edges from a and b to x
+	// and y cross, so x and y are swapped.
+	g.Edge("a", "y")
+	g.Edge("b", "x")
+	g.Edge("y", "c")
+	g.Edge("x", "d")
+
+	layout, err := g.Layout()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := map[string][2]int{
+		"a": {0, 0},
+		"b": {1, 0},
+		"y": {0, 1},
+		"x": {1, 1},
+		"c": {0, 2},
+		"d": {1, 2},
+	}
+
+	for k, p := range expected {
+		if layout[k] != p {
+			t.Errorf("expected %v for %v, got %v", p, k, layout[k])
+		}
+	}
+
+	if len(layout) != len(expected) {
+		t.Errorf("expected %v positions, got %v", len(expected), len(layout))
+	}
+
+	g.Edge("d", "b")
+
+	if _, err := g.Layout(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
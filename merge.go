@@ -0,0 +1,60 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import "fmt"
+
+// MergeStrict returns a new graph with the nodes and edges of both graphs,
+// and reports whether the merge created a cycle that neither graph had on its
+// own. That is the case when two nodes can reach each other in the merged
+// graph, but not in either of the graphs. It then returns the merged graph
+// together with an error wrapping ErrCycle that lists the nodes of the first
+// such cycle, so the merged graph can still be inspected. Cycles that already
+// exist in one of the graphs are not an error. The merged graph uses the key
+// normalizer of g, which is also applied to the keys of other.
+func (g *Graph[Key]) MergeStrict(other *Graph[Key]) (*Graph[Key], error) {
+	m := g.Copy()
+	for from, e := range other.nodes {
+		m.Node(from)
+		for to := range e {
+			m.Edge(from, to)
+		}
+	}
+
+	// The components of both graphs, by the keys of the merged graph.
+	_, gid := g.condensation()
+	_, oc := other.condensation()
+	oid := make(map[Key]int, len(oc))
+	for k, c := range oc {
+		oid[m.key(k)] = c
+	}
+
+	components, _ := m.condensation()
+	for _, c := range components {
+		if len(c) < 2 {
+			continue
+		}
+
+		// The nodes of the component must all be in the same component of g,
+		// or all in the same component of other.
+		sameIn := func(id map[Key]int) bool {
+			first, ok := id[c[0]]
+			if !ok {
+				return false
+			}
+			for _, k := range c[1:] {
+				if i, ok := id[k]; !ok || i != first {
+					return false
+				}
+			}
+			return true
+		}
+
+		if !sameIn(gid) && !sameIn(oid) {
+			sortKeys(c)
+			return m, fmt.Errorf("%w: %v", ErrCycle, c)
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,61 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMergeStrict(t *testing.T) {
+	g := New[string]()
+	other := New[string]()
+
+	// We construct two acyclic graphs, a -> b and b -> c. Merging them gives
+	// a -> b -> c.
+	g.Edge("a", "b")
+	other.Edge("b", "c")
+
+	m, err := g.MergeStrict(other)
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := map[string]Edges[string]{
+		"a": {"b": true},
+		"b": {"c": true},
+		"c": {},
+	}
+
+	if !reflect.DeepEqual(m.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, m.nodes)
+	}
+
+	// The graphs must not be modified.
+	if len(g.nodes) != 2 || len(other.nodes) != 2 {
+		t.Errorf("expected 2 nodes in both graphs, got %v and %v", len(g.nodes), len(other.nodes))
+	}
+
+	// The edge c -> a closes a cycle that neither graph has on its own.
+	other.Edge("c", "a")
+
+	if _, err := g.MergeStrict(other); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+
+	// A cycle that already exists in one of the graphs is fine, even if the
+	// other graph adds edges to it.
+	g = New[string]()
+	g.Edge("a", "b")
+	g.Edge("b", "a")
+	other = New[string]()
+	other.Edge("a", "b")
+	other.Edge("b", "c")
+
+	if _, err := g.MergeStrict(other); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
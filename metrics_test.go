@@ -0,0 +1,361 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestPageRank(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> c, b -> c,
+	// c -> d and d -> c. The nodes c and d pass their rank to each other, and
+	// c also receives the rank of a and b, so c ranks highest.
+	g.Edge("a", "c")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("d", "c")
+
+	rank := g.PageRank(0.85, 100)
+
+	sum := 0.0
+	for _, r := range rank {
+		sum += r
+	}
+
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("expected ranks to sum to 1, got %v", sum)
+	}
+
+	if !(rank["c"] > rank["d"] && rank["d"] > rank["a"]) {
+		t.Errorf("expected c > d > a, got %v", rank)
+	}
+
+	if math.Abs(rank["a"]-rank["b"]) > 1e-9 {
+		t.Errorf("expected a and b to be equal, got %v", rank)
+	}
+}
+
+func TestPageRankDangling(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b. The rank of
+	// b is distributed over both nodes. With a damping of 0.5, the ranks
+	// converge to 0.4 for a and 0.6 for b.
+	g.Edge("a", "b")
+
+	rank := g.PageRank(0.5, 100)
+
+	if math.Abs(rank["a"]-0.4) > 1e-9 || math.Abs(rank["b"]-0.6) > 1e-9 {
+		t.Errorf("expected a 0.4 and b 0.6, got %v", rank)
+	}
+}
+
+func TestWidth(t *testing.T) {
+	g := New[string]()
+
+	if w, err := g.Width(); err != nil || w != 0 {
+		t.Errorf("expected 0, got %v and %v", w, err)
+	}
+
+	// We construct a graph with the following structure: a -> b -> d,
+	// a -> c -> d, d -> e, d -> f and f -> g. The largest antichains are
+	// {b c}, {e f} and {e g}, so the width is 2, while three of the layers
+	// have a single node.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "d")
+	g.Edge("c", "d")
+	g.Edge("d", "e")
+	g.Edge("d", "f")
+	g.Edge("f", "g")
+
+	if w, err := g.Width(); err != nil || w != 2 {
+		t.Errorf("expected 2, got %v and %v", w, err)
+	}
+
+	// Independent nodes are all part of the largest antichain. The edge
+	// h -> e adds h, which cannot reach b, c or f.
+	g.Edge("h", "e")
+
+	if w, err := g.Width(); err != nil || w != 3 {
+		t.Errorf("expected 3, got %v and %v", w, err)
+	}
+
+	g.Edge("e", "a")
+
+	if _, err := g.Width(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestMaxParallelism(t *testing.T) {
+	g := New[string]()
+
+	if p, err := g.MaxParallelism(); err != nil || p != 0 {
+		t.Errorf("expected 0, got %v and %v", p, err)
+	}
+
+	// We construct a graph with the following structure: a -> b, a -> c,
+	// d -> e, e -> c and e -> f. The layers are {a d}, {b e} and {c f}, so
+	// at most two nodes run at the same time, while the width is 3 because
+	// of {b c f}.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("d", "e")
+	g.Edge("e", "c")
+	g.Edge("e", "f")
+
+	if p, err := g.MaxParallelism(); err != nil || p != 2 {
+		t.Errorf("expected 2, got %v and %v", p, err)
+	}
+
+	if w, err := g.Width(); err != nil || w != 3 {
+		t.Errorf("expected 3, got %v and %v", w, err)
+	}
+
+	g.Edge("c", "a")
+
+	if _, err := g.MaxParallelism(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestLayerEdgeCounts(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, a -> c,
+	// a -> d, x -> d, b -> d and c -> e. The layers are {a x}, {b c} and
+	// {d e}.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("a", "d")
+	g.Edge("x", "d")
+	g.Edge("b", "d")
+	g.Edge("c", "e")
+
+	counts, err := g.LayerEdgeCounts()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !reflect.DeepEqual(counts, []int{4, 2, 0}) {
+		t.Errorf("expected [4 2 0], got %v", counts)
+	}
+
+	g.Edge("e", "a")
+
+	if _, err := g.LayerEdgeCounts(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	g := New[string]()
+
+	if d, err := g.MaxDepth(); err != nil || d != 0 {
+		t.Errorf("expected 0, got %v and %v", d, err)
+	}
+
+	// We construct a graph with the following structure: a -> b -> c -> d,
+	// a -> d and x -> d.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("a", "d")
+	g.Edge("x", "d")
+
+	if d, err := g.MaxDepth(); err != nil || d != 3 {
+		t.Errorf("expected 3, got %v and %v", d, err)
+	}
+
+	g.Edge("d", "a")
+
+	if _, err := g.MaxDepth(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestHeights(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> d,
+	// a -> d and x -> d.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("a", "d")
+	g.Edge("x", "d")
+
+	heights, err := g.Heights()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := map[string]int{"a": 3, "b": 2, "c": 1, "d": 0, "x": 1}
+	if !reflect.DeepEqual(heights, expected) {
+		t.Errorf("expected %v, got %v", expected, heights)
+	}
+
+	g.Edge("d", "a")
+
+	if _, err := g.Heights(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestCriticalEdges(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> e,
+	// a -> d -> c, a -> e and x -> c. There are two longest paths, through b
+	// and through d, of three edges each.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "e")
+	g.Edge("a", "d")
+	g.Edge("d", "c")
+	g.Edge("a", "e")
+	g.Edge("x", "c")
+
+	edges, err := g.CriticalEdges()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := [][2]string{{"a", "b"}, {"a", "d"}, {"b", "c"}, {"c", "e"}, {"d", "c"}}
+	if !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected %v, got %v", expected, edges)
+	}
+
+	g.Edge("e", "a")
+
+	if _, err := g.CriticalEdges(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestNonCriticalEdges(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> e,
+	// a -> d -> c, a -> e and x -> c. The edges a -> e and x -> c are not on
+	// either longest path, through b or through d.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "e")
+	g.Edge("a", "d")
+	g.Edge("d", "c")
+	g.Edge("a", "e")
+	g.Edge("x", "c")
+
+	edges, err := g.NonCriticalEdges()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := [][2]string{{"a", "e"}, {"x", "c"}}
+	if !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected %v, got %v", expected, edges)
+	}
+
+	g.Edge("e", "a")
+
+	if _, err := g.NonCriticalEdges(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestDiameter(t *testing.T) {
+	g := New[string]()
+
+	if d := g.Diameter(); d != 0 {
+		t.Errorf("expected 0, got %v", d)
+	}
+
+	// We construct a graph with the following structure: a -> b -> c -> d,
+	// a -> c, and a separate part x -> y. The shortest path from a to d has
+	// two edges, while the path from b to d also has two.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("a", "c")
+	g.Edge("x", "y")
+
+	if d := g.Diameter(); d != 2 {
+		t.Errorf("expected 2, got %v", d)
+	}
+
+	// The edge d -> a makes every node reachable from every other, and the
+	// shortest path from b to a now has three edges.
+	g.Edge("d", "a")
+
+	if d := g.Diameter(); d != 3 {
+		t.Errorf("expected 3, got %v", d)
+	}
+}
+
+func TestClusteringCoefficient(t *testing.T) {
+	g := New[string]()
+
+	if c := g.ClusteringCoefficient(); c != 0 {
+		t.Errorf("expected 0, got %v", c)
+	}
+
+	// We construct a graph with the following structure: a -> b -> c -> a,
+	// b -> a, c -> d and d -> d. The local coefficients are 1 for a and b,
+	// 1/3 for c and 0 for d, which has a single neighbor.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "a")
+	g.Edge("b", "a")
+	g.Edge("c", "d")
+	g.Edge("d", "d")
+
+	if c := g.ClusteringCoefficient(); math.Abs(c-7.0/12) > 1e-9 {
+		t.Errorf("expected %v, got %v", 7.0/12, c)
+	}
+}
+
+func TestInfluence(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c,
+	// a -> d and e -> c. The node a reaches three of the four other nodes.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("a", "d")
+	g.Edge("e", "c")
+
+	influence, err := g.Influence()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := map[string]float64{"a": 0.75, "b": 0.25, "c": 0, "d": 0, "e": 0.25}
+	for k, v := range expected {
+		if math.Abs(influence[k]-v) > 1e-9 {
+			t.Errorf("expected %v for %v, got %v", v, k, influence[k])
+		}
+	}
+
+	g.Edge("c", "a")
+
+	if _, err := g.Influence(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+// MultiGraph represents a directed graph that can have multiple edges between
+// the same pair of nodes. It keeps track of the number of edges between every
+// pair of nodes, instead of collapsing them into one edge like Graph does.
+type MultiGraph[Key comparable] struct {
+	nodes map[Key]MultiEdges[Key]
+}
+
+// MultiEdges represents the edges of a node in a directed multigraph, with the
+// number of parallel edges to every node.
+type MultiEdges[Key comparable] map[Key]int
+
+// NewMulti returns a new multigraph.
+func NewMulti[Key comparable]() *MultiGraph[Key] {
+	return &MultiGraph[Key]{
+		nodes: make(map[Key]MultiEdges[Key]),
+	}
+}
+
+// Node returns the edges for a node. It creates the node if it does not exist.
+func (mg *MultiGraph[Key]) Node(key Key) MultiEdges[Key] {
+	n, ok := mg.nodes[key]
+	if !ok {
+		n = make(MultiEdges[Key])
+		mg.nodes[key] = n
+	}
+	return n
+}
+
+// Edge adds an edge to the graph. It creates the nodes if they do not exist.
+// Adding an edge that already exists increases its multiplicity.
+func (mg *MultiGraph[Key]) Edge(from Key, to Key) {
+	f := mg.Node(from)
+	mg.Node(to)
+	f[to]++
+}
+
+// Add adds a node and its outgoing edges to the graph. Every occurrence of a
+// key in edges adds one edge.
+func (mg *MultiGraph[Key]) Add(node Key, edges []Key) {
+	n := mg.Node(node)
+	for _, e := range edges {
+		mg.Node(e)
+		n[e]++
+	}
+}
+
+// EdgeCount returns the number of edges from one node to another. It returns 0
+// if there is no edge or if the nodes do not exist.
+func (mg *MultiGraph[Key]) EdgeCount(from, to Key) int {
+	return mg.nodes[from][to]
+}
+
+// Sort returns a topological sorted list of the graph nodes. Parallel edges do
+// not affect the order. It returns ErrCycle if the graph has a cycle.
+func (mg *MultiGraph[Key]) Sort() ([]Key, error) {
+	return mg.simple().Sort()
+}
+
+// Simplify returns a graph with the same nodes, and a single edge for every
+// pair of nodes with one or more edges between them, together with the number
+// of edges between every such pair. The graph can be sorted and analyzed like
+// any other graph, while the numbers can weight its edges.
+func (mg *MultiGraph[Key]) Simplify() (*Graph[Key], map[[2]Key]int) {
+	multiplicity := make(map[[2]Key]int)
+	for from, e := range mg.nodes {
+		for to, n := range e {
+			multiplicity[[2]Key{from, to}] = n
+		}
+	}
+
+	return mg.simple(), multiplicity
+}
+
+// simple returns a graph with the same nodes, and a single edge for every pair
+// of nodes with one or more edges between them.
+func (mg *MultiGraph[Key]) simple() *Graph[Key] {
+	g := New[Key]()
+
+	for from, e := range mg.nodes {
+		g.Node(from)
+		for to := range e {
+			g.Edge(from, to)
+		}
+	}
+
+	return g
+}
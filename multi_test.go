@@ -0,0 +1,70 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMultiGraph(t *testing.T) {
+	g := NewMulti[string]()
+
+	// We construct a graph with the following structure: a -> b three times,
+	// and b -> c once.
+	g.Edge("a", "b")
+	g.Edge("a", "b")
+	g.Add("a", []string{"b"})
+	g.Add("b", []string{"c"})
+
+	if n := g.EdgeCount("a", "b"); n != 3 {
+		t.Errorf("expected 3, got %v", n)
+	}
+
+	if n := g.EdgeCount("b", "c"); n != 1 {
+		t.Errorf("expected 1, got %v", n)
+	}
+
+	if n := g.EdgeCount("c", "a"); n != 0 {
+		t.Errorf("expected 0, got %v", n)
+	}
+
+	keys, err := g.Sort()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("expected [a b c], got %v", keys)
+	}
+}
+
+func TestMultiGraphSimplify(t *testing.T) {
+	mg := NewMulti[string]()
+
+	// We construct a graph with the following structure: a -> b twice,
+	// b -> c once and an isolated node d.
+	mg.Edge("a", "b")
+	mg.Edge("a", "b")
+	mg.Edge("b", "c")
+	mg.Node("d")
+
+	g, multiplicity := mg.Simplify()
+
+	expected := map[string]Edges[string]{
+		"a": {"b": true},
+		"b": {"c": true},
+		"c": {},
+		"d": {},
+	}
+
+	if !reflect.DeepEqual(g.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, g.nodes)
+	}
+
+	if expected := map[[2]string]int{{"a", "b"}: 2, {"b", "c"}: 1}; !reflect.DeepEqual(multiplicity, expected) {
+		t.Errorf("expected %v, got %v", expected, multiplicity)
+	}
+}
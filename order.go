@@ -0,0 +1,97 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// compareKeys compares two keys, to give methods that return keys a
+// deterministic order. Keys with an underlying string, integer or float type
+// are compared by value. All other keys are compared by their representation
+// as formatted by fmt's %v verb.
+func compareKeys[Key comparable](a, b Key) int {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+
+	// Keys of an interface type can hold values of different types, which we
+	// can only compare by their representation.
+	if va.IsValid() && vb.IsValid() && va.Kind() == vb.Kind() {
+		switch va.Kind() {
+		case reflect.String:
+			return strings.Compare(va.String(), vb.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(va.Int(), vb.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(va.Uint(), vb.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(va.Float(), vb.Float())
+		}
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// sortKeys sorts keys in place, in the order of compareKeys.
+func sortKeys[Key comparable](keys []Key) {
+	slices.SortFunc(keys, compareKeys[Key])
+}
+
+// sortEdges sorts edges in place, by their source and then by their target, in
+// the order of compareKeys.
+func sortEdges[Key comparable](edges [][2]Key) {
+	slices.SortFunc(edges, func(a, b [2]Key) int {
+		if c := compareKeys(a[0], b[0]); c != 0 {
+			return c
+		}
+		return compareKeys(a[1], b[1])
+	})
+}
+
+// keys returns the nodes of the graph, in the order of compareKeys.
+func (g *Graph[Key]) keys() []Key {
+	keys := make([]Key, 0, len(g.nodes))
+	for k := range g.nodes {
+		keys = append(keys, k)
+	}
+	sortKeys(keys)
+	return keys
+}
+
+// edges returns the edges of the graph, in the order of sortEdges.
+func (g *Graph[Key]) edges() [][2]Key {
+	var edges [][2]Key
+	for from, e := range g.nodes {
+		for to := range e {
+			edges = append(edges, [2]Key{from, to})
+		}
+	}
+	sortEdges(edges)
+	return edges
+}
+
+// Index assigns every node a dense integer id from 0 to the number of nodes
+// minus one, and returns both the ids of the keys and the keys of the ids. The
+// ids follow a deterministic order, so equal graphs get equal ids. For an
+// acyclic graph, it is a topological order in which ties between ready nodes
+// are broken by key. For a cyclic graph, the nodes are ordered by key. Keys
+// with an underlying string, integer or float type are ordered by value; other
+// keys are ordered by their representation as formatted by fmt's %v verb.
+func (g *Graph[Key]) Index() (ids map[Key]int, keys []Key) {
+	keys, err := g.sortFunc(func(a, b Key) bool {
+		return compareKeys(a, b) < 0
+	})
+	if err != nil {
+		keys = g.keys()
+	}
+
+	ids = make(map[Key]int, len(keys))
+	for i, k := range keys {
+		ids[k] = i
+	}
+
+	return ids, keys
+}
@@ -0,0 +1,73 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortKeys(t *testing.T) {
+	// Integers are sorted by value, not by their representation.
+	ints := []int{10, 2, -1, 7}
+	sortKeys(ints)
+
+	if !reflect.DeepEqual(ints, []int{-1, 2, 7, 10}) {
+		t.Errorf("expected [-1 2 7 10], got %v", ints)
+	}
+
+	// Named string types are sorted by value.
+	type name string
+	names := []name{"b", "c", "a"}
+	sortKeys(names)
+
+	if !reflect.DeepEqual(names, []name{"a", "b", "c"}) {
+		t.Errorf("expected [a b c], got %v", names)
+	}
+
+	// Other types are sorted by their representation.
+	type pair struct{ a, b int }
+	pairs := []pair{{2, 1}, {1, 2}, {1, 1}}
+	sortKeys(pairs)
+
+	if !reflect.DeepEqual(pairs, []pair{{1, 1}, {1, 2}, {2, 1}}) {
+		t.Errorf("expected [{1 1} {1 2} {2 1}], got %v", pairs)
+	}
+
+	// Interface keys can hold values of different types.
+	mixed := []any{"a", 2, 1}
+	sortKeys(mixed)
+
+	if !reflect.DeepEqual(mixed, []any{1, 2, "a"}) {
+		t.Errorf("expected [1 2 a], got %v", mixed)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: c -> a, c -> b,
+	// d -> a. The smallest topological order by key is [c b d a].
+	g.Edge("c", "a")
+	g.Edge("c", "b")
+	g.Edge("d", "a")
+
+	ids, keys := g.Index()
+
+	if !reflect.DeepEqual(keys, []string{"c", "b", "d", "a"}) {
+		t.Errorf("expected [c b d a], got %v", keys)
+	}
+
+	if !reflect.DeepEqual(ids, map[string]int{"c": 0, "b": 1, "d": 2, "a": 3}) {
+		t.Errorf("expected map[a:3 b:1 c:0 d:2], got %v", ids)
+	}
+
+	// With a cycle, the nodes are ordered by key.
+	g.Edge("a", "c")
+
+	_, keys = g.Index()
+
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c", "d"}) {
+		t.Errorf("expected [a b c d], got %v", keys)
+	}
+}
@@ -0,0 +1,172 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Process calls fn for every node of the graph, using up to workers
+// goroutines. It respects the order of Sort: fn is only called for a node
+// after it returned successfully for every node with an edge to it. Nodes that
+// do not depend on each other are processed concurrently.
+//
+// Process stops calling fn for new nodes as soon as fn returns an error, also
+// for nodes that were already ready. It waits for the nodes that are already
+// being processed, and then returns the first error. It returns ErrCycle
+// without calling fn if the graph has a cycle. A workers value below 1 is
+// treated as 1.
+func (g *Graph[Key]) Process(workers int, fn func(Key) error) error {
+	// We check for cycles first, so we do not process anything when the
+	// graph cannot be processed completely.
+	if !g.kahn(func(Key) bool { return true }) {
+		return ErrCycle
+	}
+
+	workers = max(workers, 1)
+
+	indegree := g.InDegrees()
+
+	type result struct {
+		key Key
+		err error
+	}
+
+	// Every node is sent to the tasks channel at most once, so with this
+	// capacity sending never blocks.
+	tasks := make(chan Key, len(g.nodes))
+	results := make(chan result)
+
+	// stop is set by the first call of fn that fails, so the workers skip the
+	// nodes that are still queued.
+	var stop atomic.Bool
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range tasks {
+				if stop.Load() {
+					results <- result{key: k}
+					continue
+				}
+				err := fn(k)
+				if err != nil {
+					stop.Store(true)
+				}
+				results <- result{k, err}
+			}
+		}()
+	}
+
+	inflight := 0
+	for k := range g.nodes {
+		if indegree[k] == 0 {
+			tasks <- k
+			inflight++
+		}
+	}
+
+	var err error
+	for inflight > 0 {
+		r := <-results
+		inflight--
+
+		if r.err != nil {
+			if err == nil {
+				err = r.err
+			}
+			continue
+		}
+
+		// After an error, we only wait for the nodes that are in flight.
+		if err != nil {
+			continue
+		}
+
+		for m := range g.nodes[r.key] {
+			indegree[m]--
+			if indegree[m] == 0 {
+				tasks <- m
+				inflight++
+			}
+		}
+	}
+
+	close(tasks)
+	wg.Wait()
+
+	return err
+}
+
+// ScheduleRounds divides the nodes of the graph into rounds of at most workers
+// nodes, such that every node is in a later round than all nodes with an edge
+// to it. It models running the graph on a fixed number of workers, where every
+// node takes the same time, so the number of rounds is the time it takes.
+// Every round is filled with as many ready nodes as possible. When more nodes
+// are ready than there are workers, the nodes with the longest path after
+// them go first, so the nodes that hold up the most others start early. Ties
+// are broken by key, and the nodes of every round are sorted by key, so the
+// rounds are deterministic. This greedy packing is a heuristic: it needs the
+// fewest rounds when there are enough workers, or when every node has at most
+// one outgoing edge, but not always otherwise. It returns ErrCycle if the
+// graph has a cycle. A workers value below 1 is treated as 1.
+func (g *Graph[Key]) ScheduleRounds(workers int) ([][]Key, error) {
+	sorted, err := g.Sort()
+	if err != nil {
+		return nil, err
+	}
+
+	workers = max(workers, 1)
+
+	// The number of nodes on the longest path starting at every node.
+	height := make(map[Key]int, len(sorted))
+	for i := len(sorted) - 1; i >= 0; i-- {
+		n := sorted[i]
+		height[n] = 1
+		for m := range g.nodes[n] {
+			height[n] = max(height[n], height[m]+1)
+		}
+	}
+
+	indegree := g.InDegrees()
+
+	var ready []Key
+	for k := range g.nodes {
+		if indegree[k] == 0 {
+			ready = append(ready, k)
+		}
+	}
+
+	var rounds [][]Key
+	for len(ready) > 0 {
+		slices.SortFunc(ready, func(a, b Key) int {
+			if c := height[b] - height[a]; c != 0 {
+				return c
+			}
+			return compareKeys(a, b)
+		})
+
+		n := min(workers, len(ready))
+		round := slices.Clone(ready[:n])
+		ready = ready[n:]
+		sortKeys(round)
+
+		// The nodes of a round only become ready for the next round.
+		for _, k := range round {
+			for m := range g.nodes[k] {
+				indegree[m]--
+				if indegree[m] == 0 {
+					ready = append(ready, m)
+				}
+			}
+		}
+
+		rounds = append(rounds, round)
+	}
+
+	return rounds, nil
+}
@@ -0,0 +1,144 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestProcess(t *testing.T) {
+	g := buildGraph(1000)
+
+	// We record the order in which the nodes are processed, and check that
+	// every node is processed after the nodes with an edge to it.
+	var mu sync.Mutex
+	var order []int
+
+	err := g.Process(8, func(k int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, k)
+		return nil
+	})
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	checkOrder(t, g, order)
+}
+
+func TestProcessError(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c. When
+	// processing b fails, c must not be processed.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+
+	failed := errors.New("failed")
+
+	var processed []string
+	err := g.Process(2, func(k string) error {
+		processed = append(processed, k)
+		if k == "b" {
+			return failed
+		}
+		return nil
+	})
+
+	if !errors.Is(err, failed) {
+		t.Errorf("expected failed, got %v", err)
+	}
+
+	if len(processed) != 2 {
+		t.Errorf("expected [a b], got %v", processed)
+	}
+
+	g.Edge("c", "a")
+
+	err = g.Process(2, func(k string) error {
+		t.Errorf("expected no call, got %v", k)
+		return nil
+	})
+
+	if !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestProcessErrorSkipsReady(t *testing.T) {
+	g := New[int]()
+
+	// We construct a graph of 100 nodes without edges, so all of them are
+	// ready at once. After the first error, only the calls that already
+	// started on the other workers may still happen.
+	for i := 0; i < 100; i++ {
+		g.Node(i)
+	}
+
+	failed := errors.New("failed")
+
+	for _, workers := range []int{1, 4} {
+		var calls atomic.Int32
+		err := g.Process(workers, func(int) error {
+			calls.Add(1)
+			return failed
+		})
+
+		if !errors.Is(err, failed) {
+			t.Errorf("expected failed, got %v", err)
+		}
+
+		if n := int(calls.Load()); n > workers {
+			t.Errorf("expected at most %v calls, got %v", workers, n)
+		}
+	}
+}
+
+func TestScheduleRounds(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: x -> y -> z and
+	// the isolated nodes a, b and c. The chain goes first, so two workers
+	// need three rounds.
+	g.Edge("x", "y")
+	g.Edge("y", "z")
+	g.Node("a")
+	g.Node("b")
+	g.Node("c")
+
+	rounds, err := g.ScheduleRounds(2)
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := [][]string{{"a", "x"}, {"b", "y"}, {"c", "z"}}
+	if !reflect.DeepEqual(rounds, expected) {
+		t.Errorf("expected %v, got %v", expected, rounds)
+	}
+
+	rounds, err = g.ScheduleRounds(0)
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(rounds) != 6 {
+		t.Errorf("expected 6 rounds, got %v", rounds)
+	}
+
+	g.Edge("z", "x")
+
+	if _, err := g.ScheduleRounds(2); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
@@ -0,0 +1,617 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import "math/big"
+
+// reachable returns the set of nodes that can be reached from any of the
+// start nodes by following one or more edges. A start node is only part of the
+// set if it can be reached from a start node, for example because it is on a
+// cycle. Passing the nodes of a reversed graph gives the ancestors instead of
+// the descendants.
+func reachable[Key comparable](nodes map[Key]Edges[Key], start ...Key) map[Key]bool {
+	visited := make(map[Key]bool)
+
+	var stack []Key
+	for _, s := range start {
+		for m := range nodes[s] {
+			if !visited[m] {
+				visited[m] = true
+				stack = append(stack, m)
+			}
+		}
+	}
+
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for m := range nodes[n] {
+			if !visited[m] {
+				visited[m] = true
+				stack = append(stack, m)
+			}
+		}
+	}
+
+	return visited
+}
+
+// CommonDescendants returns the nodes that can be reached from every given
+// node, in a deterministic order. A given node is only part of the result if
+// it can be reached from all given nodes, including itself. It returns nil if
+// no nodes are given or if one of them does not exist.
+//
+// The descendants of the nodes are intersected one node at a time, and it
+// stops as soon as the intersection is empty.
+func (g *Graph[Key]) CommonDescendants(keys ...Key) []Key {
+	var common map[Key]bool
+
+	for _, k := range keys {
+		k = g.key(k)
+		if _, ok := g.nodes[k]; !ok {
+			return nil
+		}
+
+		d := reachable(g.nodes, k)
+		if common == nil {
+			common = d
+		} else {
+			for c := range common {
+				if !d[c] {
+					delete(common, c)
+				}
+			}
+		}
+
+		if len(common) == 0 {
+			return nil
+		}
+	}
+
+	var result []Key
+	for k := range common {
+		result = append(result, k)
+	}
+	sortKeys(result)
+
+	return result
+}
+
+// FirstCommonAncestor returns the nearest node from which both a and b can be
+// reached: of all common ancestors, the one that comes last in the order of
+// Index, which is a topological order. None of the other common ancestors can
+// be reached from it, so it is where the dependency chains of a and b last
+// agree. Like CommonDescendants, a and b are only ancestors of themselves if
+// they are on a cycle, so if a can be reached from b, b is not the result. It
+// returns false if there is no common ancestor, if one of the nodes does not
+// exist or if the graph has a cycle.
+func (g *Graph[Key]) FirstCommonAncestor(a, b Key) (Key, bool) {
+	var zero Key
+
+	a, b = g.key(a), g.key(b)
+	if _, ok := g.nodes[a]; !ok {
+		return zero, false
+	}
+	if _, ok := g.nodes[b]; !ok {
+		return zero, false
+	}
+
+	// The positions in a topological order in which ties are broken by key,
+	// like the order of Index, so the result is deterministic.
+	sorted, err := g.sortFunc(func(a, b Key) bool {
+		return compareKeys(a, b) < 0
+	})
+	if err != nil {
+		return zero, false
+	}
+
+	ids := make(map[Key]int, len(sorted))
+	for i, k := range sorted {
+		ids[k] = i
+	}
+
+	r := g.Reverse()
+	ancestors := reachable(r.nodes, b)
+
+	var first Key
+	found := false
+	for k := range reachable(r.nodes, a) {
+		if ancestors[k] && (!found || ids[k] > ids[first]) {
+			first, found = k, true
+		}
+	}
+
+	return first, found
+}
+
+// FirstCommonDescendant returns the nearest node that can be reached from both
+// a and b: of all common descendants, the one that comes first in the order of
+// Index, which is a topological order. It cannot be reached from any of the
+// other common descendants, so it is where the dependency chains of a and b
+// first join. It is the dual of FirstCommonAncestor, and like it, if b can be
+// reached from a, b is not the result unless it is on a cycle. It returns
+// false if there is no common descendant, if one of the nodes does not exist
+// or if the graph has a cycle.
+func (g *Graph[Key]) FirstCommonDescendant(a, b Key) (Key, bool) {
+	var zero Key
+
+	a, b = g.key(a), g.key(b)
+	if _, ok := g.nodes[a]; !ok {
+		return zero, false
+	}
+	if _, ok := g.nodes[b]; !ok {
+		return zero, false
+	}
+
+	// The positions in a topological order in which ties are broken by key,
+	// like the order of Index, so the result is deterministic.
+	sorted, err := g.sortFunc(func(a, b Key) bool {
+		return compareKeys(a, b) < 0
+	})
+	if err != nil {
+		return zero, false
+	}
+
+	ids := make(map[Key]int, len(sorted))
+	for i, k := range sorted {
+		ids[k] = i
+	}
+
+	descendants := reachable(g.nodes, b)
+
+	var first Key
+	found := false
+	for k := range reachable(g.nodes, a) {
+		if descendants[k] && (!found || ids[k] < ids[first]) {
+			first, found = k, true
+		}
+	}
+
+	return first, found
+}
+
+// NonTerminating returns the nodes from which no sink can be reached, in a
+// deterministic order. Every path from these nodes eventually loops, so in a
+// state machine they are states that can never be left for a final state. It
+// takes O(n) time for n = [number of nodes] + [number of edges].
+func (g *Graph[Key]) NonTerminating() []Key {
+	// The nodes that can reach a sink are the sinks and their ancestors.
+	sinks := g.Sinks()
+	terminating := reachable(g.Reverse().nodes, sinks...)
+	for _, s := range sinks {
+		terminating[s] = true
+	}
+
+	var keys []Key
+	for k := range g.nodes {
+		if !terminating[k] {
+			keys = append(keys, k)
+		}
+	}
+	sortKeys(keys)
+
+	return keys
+}
+
+// ReachableAny reports whether any of the targets can be reached from any of
+// the sources. A source that is also a target counts as reached. It searches
+// from all sources at once, and stops as soon as it reaches a target, so it
+// takes at most O(n) time for n = [number of nodes] + [number of edges],
+// regardless of the number of sources and targets. Keys that do not exist are
+// ignored.
+func (g *Graph[Key]) ReachableAny(sources, targets []Key) bool {
+	target := make(map[Key]bool, len(targets))
+	for _, k := range targets {
+		target[g.key(k)] = true
+	}
+
+	visited := make(map[Key]bool)
+	var queue []Key
+	for _, k := range sources {
+		k = g.key(k)
+		if _, ok := g.nodes[k]; ok && !visited[k] {
+			visited[k] = true
+			queue = append(queue, k)
+		}
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		if target[n] {
+			return true
+		}
+
+		for m := range g.nodes[n] {
+			if !visited[m] {
+				visited[m] = true
+				queue = append(queue, m)
+			}
+		}
+	}
+
+	return false
+}
+
+// Comparable reports whether a can be reached from b or b from a, so that the
+// two nodes are ordered in the partial order of the graph. Nodes that are not
+// comparable can be processed in either order, or at the same time. Every node
+// is comparable to itself. It returns false if one of the nodes does not
+// exist.
+func (g *Graph[Key]) Comparable(a, b Key) bool {
+	return g.ReachableAny([]Key{a}, []Key{b}) || g.ReachableAny([]Key{b}, []Key{a})
+}
+
+// RelativeTo splits all nodes but pivot by how they are ordered relative to it:
+// before are the nodes from which pivot can be reached, after are the nodes
+// that can be reached from pivot, and unordered are the nodes that are not
+// comparable to it, see Comparable. When placing a new task next to pivot,
+// only before and after constrain where it goes. Each of them is in a
+// deterministic order. A node on a cycle with pivot is in both before and
+// after. It returns nil for all three if pivot does not exist.
+func (g *Graph[Key]) RelativeTo(pivot Key) (before, after, unordered []Key) {
+	pivot = g.key(pivot)
+	if _, ok := g.nodes[pivot]; !ok {
+		return nil, nil, nil
+	}
+
+	ancestors := reachable(g.Reverse().nodes, pivot)
+	descendants := reachable(g.nodes, pivot)
+
+	for _, k := range g.keys() {
+		if k == pivot {
+			continue
+		}
+		if ancestors[k] {
+			before = append(before, k)
+		}
+		if descendants[k] {
+			after = append(after, k)
+		}
+		if !ancestors[k] && !descendants[k] {
+			unordered = append(unordered, k)
+		}
+	}
+
+	return before, after, unordered
+}
+
+// Between returns the nodes that are on a path from one node to another,
+// including both nodes, in a deterministic order. These are the nodes that
+// can be reached from from and can reach to, which explains why from depends
+// on to. It returns nil if to cannot be reached from from, or if one of them
+// does not exist. For from == to, it returns the node and the nodes on a cycle
+// through it.
+func (g *Graph[Key]) Between(from, to Key) []Key {
+	from, to = g.key(from), g.key(to)
+	if _, ok := g.nodes[from]; !ok {
+		return nil
+	}
+	if _, ok := g.nodes[to]; !ok {
+		return nil
+	}
+
+	descendants := reachable(g.nodes, from)
+	if from != to && !descendants[to] {
+		return nil
+	}
+	descendants[from] = true
+
+	ancestors := reachable(g.Reverse().nodes, to)
+	ancestors[to] = true
+
+	var between []Key
+	for k := range descendants {
+		if ancestors[k] {
+			between = append(between, k)
+		}
+	}
+	sortKeys(between)
+
+	return between
+}
+
+// DependencyCounts returns, for every node, the number of distinct nodes that
+// can be reached from it, which are the nodes it depends on directly or
+// indirectly. It returns ErrCycle if the graph has a cycle.
+//
+// The sets of reachable nodes are built in reverse topological order, as the
+// union of the sets of the nodes a node has an edge to, so every node is only
+// searched once. A set is dropped as soon as all nodes with an edge to it have
+// used it, but in the worst case, such as a long chain, O(n^2) memory is
+// needed for n = [number of nodes]. Counting the reachable nodes of every
+// node with a separate search needs less memory, but more time.
+func (g *Graph[Key]) DependencyCounts() (map[Key]int, error) {
+	sorted, err := g.Sort()
+	if err != nil {
+		return nil, err
+	}
+
+	// The number of nodes with an edge to every node that have not been
+	// handled yet. When it drops to 0, the set of the node is not needed
+	// anymore.
+	pending := g.InDegrees()
+
+	sets := make(map[Key]map[Key]bool, len(g.nodes))
+	counts := make(map[Key]int, len(g.nodes))
+
+	for i := len(sorted) - 1; i >= 0; i-- {
+		n := sorted[i]
+
+		set := make(map[Key]bool)
+		for m := range g.nodes[n] {
+			set[m] = true
+			for k := range sets[m] {
+				set[k] = true
+			}
+
+			pending[m]--
+			if pending[m] == 0 {
+				delete(sets, m)
+			}
+		}
+
+		counts[n] = len(set)
+		if pending[n] > 0 {
+			sets[n] = set
+		}
+	}
+
+	return counts, nil
+}
+
+// BlastRadii returns, for every node, the number of distinct nodes from which
+// it can be reached, which are the nodes that depend on it directly or
+// indirectly when an edge a -> b means that a depends on b. These are the
+// nodes affected when it breaks, see ImpactOf. It returns ErrCycle if the
+// graph has a cycle.
+//
+// BlastRadii is DependencyCounts of the reversed graph, so all counts are
+// computed in a single pass, with the memory use of DependencyCounts.
+func (g *Graph[Key]) BlastRadii() (map[Key]int, error) {
+	return g.Reverse().DependencyCounts()
+}
+
+// ReachableCount returns the number of nodes that can be reached from a node,
+// not counting the node itself, even if it is on a cycle. It only keeps the set
+// of visited nodes, without listing them. It returns 0 if the node does not
+// exist.
+func (g *Graph[Key]) ReachableCount(key Key) int {
+	key = g.key(key)
+
+	visited := reachable(g.nodes, key)
+	if visited[key] {
+		return len(visited) - 1
+	}
+	return len(visited)
+}
+
+// BestRoot returns the node without incoming edges from which the most nodes
+// can be reached, and the number of nodes it reaches, as ReachableCount. When a
+// graph has several entry points, this is the one that covers the largest part
+// of the graph. Ties are broken by key, deterministically. It returns the zero
+// key and 0 if no node is without incoming edges, for example in an empty
+// graph.
+func (g *Graph[Key]) BestRoot() (Key, int) {
+	indegree := g.InDegrees()
+
+	var best Key
+	coverage := -1
+	for _, k := range g.keys() {
+		if indegree[k] != 0 {
+			continue
+		}
+		if c := g.ReachableCount(k); c > coverage {
+			best, coverage = k, c
+		}
+	}
+
+	return best, max(coverage, 0)
+}
+
+// ImpliedEdges returns the nodes that can be reached from a node, but that it
+// has no edge to, in a deterministic order. These are the nodes it depends on
+// only indirectly, which a transitive closure would add edges to. The node
+// itself is not included, even if it is on a cycle. It returns nil if the node
+// does not exist.
+func (g *Graph[Key]) ImpliedEdges(key Key) []Key {
+	key = g.key(key)
+
+	var implied []Key
+	for k := range reachable(g.nodes, key) {
+		if k != key && !g.nodes[key][k] {
+			implied = append(implied, k)
+		}
+	}
+	sortKeys(implied)
+
+	return implied
+}
+
+// ReachabilityBitsets returns, for every node, the set of nodes that can be
+// reached from it as a bitset, in which bit i is set if the node keys[i] can be
+// reached. Like elsewhere, a node only reaches itself if it is on a cycle.
+// Bitsets can be combined with the methods of big.Int, such as And and Or, to
+// compare what nodes reach. The order of keys is the order of Index.
+//
+// The bitsets are built from the strongly connected components, from the
+// components without outgoing edges up, so every edge is followed only once.
+// Every bitset takes n/8 bytes, so all of them together take O(n^2/64) words
+// of memory for n = [number of nodes]: about 110 MB for 30,000 nodes.
+func (g *Graph[Key]) ReachabilityBitsets() (map[Key]*big.Int, []Key) {
+	ids, keys := g.Index()
+	components, id := g.condensation()
+
+	// The bitset of every component. The components are in reverse
+	// topological order, so the bitsets of the components a component has an
+	// edge to are computed first.
+	bits := make([]*big.Int, len(components))
+	for i, c := range components {
+		b := new(big.Int)
+		cyclic := len(c) > 1
+		for _, from := range c {
+			for to := range g.nodes[from] {
+				if j := id[to]; j != i {
+					b.Or(b, bits[j])
+					b.SetBit(b, ids[to], 1)
+				} else {
+					cyclic = true
+				}
+			}
+		}
+		if cyclic {
+			for _, k := range c {
+				b.SetBit(b, ids[k], 1)
+			}
+		}
+		bits[i] = b
+	}
+
+	sets := make(map[Key]*big.Int, len(keys))
+	for k, c := range id {
+		sets[k] = new(big.Int).Set(bits[c])
+	}
+
+	return sets, keys
+}
+
+// Reachability answers whether one node can be reached from another, like
+// ReachabilityBitsets, but only computes the nodes a node reaches when it is
+// first asked about that node, and keeps them for later queries. It is cheaper
+// than computing the reachability of every node when queries only start from
+// some of the nodes. Use Graph.Reachability to create one.
+//
+// A Reachability follows the changes to its graph: the first query after a
+// change discards everything computed so far. Like Dirty, it does not notice
+// changes made directly to the Edges returned by Node or Lookup. It is not
+// safe for concurrent use, as queries update it.
+type Reachability[Key comparable] struct {
+	g *Graph[Key]
+
+	// gen is the generation of g the sets were computed for.
+	gen uint64
+
+	// sets are the nodes that can be reached from every node queried so
+	// far.
+	sets map[Key]map[Key]bool
+}
+
+// Reachability returns a Reachability for the graph, which computes what every
+// node reaches as it is needed.
+func (g *Graph[Key]) Reachability() *Reachability[Key] {
+	return &Reachability[Key]{g: g, gen: g.gen, sets: make(map[Key]map[Key]bool)}
+}
+
+// Can reports whether to can be reached from from by following one or more
+// edges. Like elsewhere, a node only reaches itself if it is on a cycle. It
+// returns false if one of the nodes does not exist. The first query from a node
+// searches the graph from it, which takes O(n) time for n = [number of nodes]
+// + [number of edges], and later queries from the same node only take a map
+// lookup.
+func (r *Reachability[Key]) Can(from, to Key) bool {
+	if r.gen != r.g.gen {
+		clear(r.sets)
+		r.gen = r.g.gen
+	}
+
+	from, to = r.g.key(from), r.g.key(to)
+	if _, ok := r.g.nodes[from]; !ok {
+		return false
+	}
+
+	set, ok := r.sets[from]
+	if !ok {
+		set = reachable(r.g.nodes, from)
+		r.sets[from] = set
+	}
+
+	return set[to]
+}
+
+// EdgeCriticality returns the number of pairs of nodes (a, b) for which b can
+// be reached from a, but no longer if the edge from -> to is removed. A low
+// number means the edge is not essential for the connections of the graph, so
+// it is a good candidate to remove, for example to break a cycle. Like
+// elsewhere, a node on a cycle reaches itself, so removing an edge of a cycle
+// also loses pairs (a, a). It returns 0 if the edge does not exist.
+//
+// Only pairs starting at from or one of its ancestors can be lost, so it
+// compares the nodes those can reach with and without the edge, which takes
+// O(k * (n + m)) time for k = [number of ancestors of from], n = [number of
+// nodes] and m = [number of edges].
+func (g *Graph[Key]) EdgeCriticality(from, to Key) int {
+	from, to = g.key(from), g.key(to)
+	if !g.nodes[from][to] {
+		return 0
+	}
+
+	without := g.Copy()
+	without.unlink(from, to)
+
+	sources := reachable(g.Reverse().nodes, from)
+	sources[from] = true
+
+	lost := 0
+	for a := range sources {
+		lost += len(reachable(g.nodes, a)) - len(reachable(without.nodes, a))
+	}
+
+	return lost
+}
+
+// ImpactOf returns the nodes that depend on any of the changed nodes, directly
+// or indirectly, in a deterministic order, such as everything to test again
+// when those nodes change. An edge a -> b is taken to mean that a depends on
+// b, as with Add, so these are the nodes from which a changed node can be
+// reached: the ancestors of the changed nodes. A changed node is only part of
+// the result if it depends on a changed node, for example because it is on a
+// cycle. Keys that do not exist are ignored. It searches backwards from all
+// changed nodes at once, which takes O(n) time for n = [number of nodes] +
+// [number of edges].
+func (g *Graph[Key]) ImpactOf(changed ...Key) []Key {
+	start := make([]Key, len(changed))
+	for i, k := range changed {
+		start[i] = g.key(k)
+	}
+
+	var impact []Key
+	for k := range reachable(g.Reverse().nodes, start...) {
+		impact = append(impact, k)
+	}
+	sortKeys(impact)
+
+	return impact
+}
+
+// InvalidationFrontier returns the nodes from which to evaluate again after the
+// dirty nodes changed, in a deterministic order, when an edge a -> b means
+// that a depends on b. The affected nodes are the dirty nodes and the nodes
+// that depend on them, see ImpactOf, and the frontier is the smallest set of
+// affected nodes from which all affected nodes can be reached: the affected
+// nodes on which no other node depends. Like MinimumRootCover, it contains one
+// node of every cycle that nothing outside of it depends on, the one with the
+// smallest key. No node of the frontier can be reached from another, so
+// evaluating from each of them visits every affected node, and starts no
+// higher than needed. Keys that do not exist are ignored, and it returns nil
+// if none of the dirty nodes exist.
+func (g *Graph[Key]) InvalidationFrontier(dirty []Key) []Key {
+	var start []Key
+	for _, k := range dirty {
+		k = g.key(k)
+		if _, ok := g.nodes[k]; ok {
+			start = append(start, k)
+		}
+	}
+
+	// Every node that depends on an affected node is affected too, so the
+	// affected nodes without incoming edges from other affected nodes have no
+	// incoming edges from other nodes at all.
+	affected := reachable(g.Reverse().nodes, start...)
+	for _, k := range start {
+		affected[k] = true
+	}
+
+	return g.subgraph(affected).MinimumRootCover()
+}
@@ -0,0 +1,548 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCommonDescendants(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> c -> d,
+	// b -> c, b -> e and a -> f. The common descendants of a and b are c and
+	// d.
+	g.Edge("a", "c")
+	g.Edge("c", "d")
+	g.Edge("b", "c")
+	g.Edge("b", "e")
+	g.Edge("a", "f")
+
+	if keys := g.CommonDescendants("a", "b"); !reflect.DeepEqual(keys, []string{"c", "d"}) {
+		t.Errorf("expected [c d], got %v", keys)
+	}
+
+	if keys := g.CommonDescendants("a", "b", "c"); !reflect.DeepEqual(keys, []string{"d"}) {
+		t.Errorf("expected [d], got %v", keys)
+	}
+
+	if keys := g.CommonDescendants("a", "e"); keys != nil {
+		t.Errorf("expected nil, got %v", keys)
+	}
+
+	if keys := g.CommonDescendants("a", "x"); keys != nil {
+		t.Errorf("expected nil, got %v", keys)
+	}
+}
+
+func TestFirstCommonAncestor(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: r -> a -> x,
+	// r -> m -> b, m -> y and a -> y. The common ancestors of x and y are r
+	// and a, of which a is the nearest.
+	g.Edge("r", "a")
+	g.Edge("a", "x")
+	g.Edge("r", "m")
+	g.Edge("m", "y")
+	g.Edge("a", "y")
+	g.Edge("m", "b")
+
+	if k, ok := g.FirstCommonAncestor("x", "y"); !ok || k != "a" {
+		t.Errorf("expected a, got %v", k)
+	}
+
+	if k, ok := g.FirstCommonAncestor("b", "y"); !ok || k != "m" {
+		t.Errorf("expected m, got %v", k)
+	}
+
+	if k, ok := g.FirstCommonAncestor("r", "y"); ok {
+		t.Errorf("expected no common ancestor, got %v", k)
+	}
+
+	if k, ok := g.FirstCommonAncestor("x", "z"); ok {
+		t.Errorf("expected no common ancestor, got %v", k)
+	}
+
+	g.Edge("y", "r")
+
+	if k, ok := g.FirstCommonAncestor("x", "y"); ok {
+		t.Errorf("expected no common ancestor, got %v", k)
+	}
+}
+
+func TestFirstCommonDescendant(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> x -> j -> z,
+	// b -> y -> j, b -> z and c -> y. The common descendants of a and b are j
+	// and z, of which j is the nearest.
+	g.Edge("a", "x")
+	g.Edge("x", "j")
+	g.Edge("j", "z")
+	g.Edge("b", "y")
+	g.Edge("y", "j")
+	g.Edge("b", "z")
+	g.Edge("c", "y")
+
+	if k, ok := g.FirstCommonDescendant("a", "b"); !ok || k != "j" {
+		t.Errorf("expected j, got %v", k)
+	}
+
+	if k, ok := g.FirstCommonDescendant("b", "c"); !ok || k != "y" {
+		t.Errorf("expected y, got %v", k)
+	}
+
+	if k, ok := g.FirstCommonDescendant("b", "y"); !ok || k != "j" {
+		t.Errorf("expected j, got %v", k)
+	}
+
+	if k, ok := g.FirstCommonDescendant("a", "z"); ok {
+		t.Errorf("expected no common descendant, got %v", k)
+	}
+
+	if k, ok := g.FirstCommonDescendant("a", "q"); ok {
+		t.Errorf("expected no common descendant, got %v", k)
+	}
+
+	g.Edge("z", "a")
+
+	if k, ok := g.FirstCommonDescendant("a", "b"); ok {
+		t.Errorf("expected no common descendant, got %v", k)
+	}
+}
+
+func TestNonTerminating(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> b,
+	// a -> d, e -> e and x -> e. From b, c, e and x, no sink can be reached.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "b")
+	g.Edge("a", "d")
+	g.Edge("e", "e")
+	g.Edge("x", "e")
+
+	keys := g.NonTerminating()
+
+	if !reflect.DeepEqual(keys, []string{"b", "c", "e", "x"}) {
+		t.Errorf("expected [b c e x], got %v", keys)
+	}
+}
+
+func TestReachableAny(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c and
+	// d -> e.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("d", "e")
+
+	for _, c := range []struct {
+		sources, targets []string
+		reachable        bool
+	}{
+		{[]string{"a"}, []string{"c"}, true},
+		{[]string{"d", "b"}, []string{"e", "x"}, true},
+		{[]string{"c"}, []string{"a"}, false},
+		{[]string{"a", "b"}, []string{"d", "e"}, false},
+		{[]string{"d"}, []string{"d"}, true},
+		{[]string{"x"}, []string{"x"}, false},
+		{nil, []string{"a"}, false},
+	} {
+		if r := g.ReachableAny(c.sources, c.targets); r != c.reachable {
+			t.Errorf("expected %v for %v to %v, got %v", c.reachable, c.sources, c.targets, r)
+		}
+	}
+}
+
+func TestComparable(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c and
+	// a -> d. The nodes b and d are not comparable.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("a", "d")
+
+	if !g.Comparable("a", "c") || !g.Comparable("c", "a") {
+		t.Errorf("expected a and c to be comparable")
+	}
+
+	if g.Comparable("b", "d") {
+		t.Errorf("expected b and d not to be comparable")
+	}
+
+	if !g.Comparable("d", "d") {
+		t.Errorf("expected d to be comparable to itself")
+	}
+
+	if g.Comparable("a", "x") || g.Comparable("x", "x") {
+		t.Errorf("expected missing nodes not to be comparable")
+	}
+}
+
+func TestRelativeTo(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> p -> c,
+	// a -> d and p -> e -> f.
+	g.Edge("a", "b")
+	g.Edge("b", "p")
+	g.Edge("p", "c")
+	g.Edge("a", "d")
+	g.Edge("p", "e")
+	g.Edge("e", "f")
+
+	before, after, unordered := g.RelativeTo("p")
+
+	if !reflect.DeepEqual(before, []string{"a", "b"}) {
+		t.Errorf("expected [a b], got %v", before)
+	}
+	if !reflect.DeepEqual(after, []string{"c", "e", "f"}) {
+		t.Errorf("expected [c e f], got %v", after)
+	}
+	if !reflect.DeepEqual(unordered, []string{"d"}) {
+		t.Errorf("expected [d], got %v", unordered)
+	}
+
+	// A cycle through p puts the nodes on it in both before and after.
+	g.Edge("c", "b")
+
+	before, after, _ = g.RelativeTo("p")
+
+	if !reflect.DeepEqual(before, []string{"a", "b", "c"}) {
+		t.Errorf("expected [a b c], got %v", before)
+	}
+	if !reflect.DeepEqual(after, []string{"b", "c", "e", "f"}) {
+		t.Errorf("expected [b c e f], got %v", after)
+	}
+
+	if before, after, unordered := g.RelativeTo("x"); before != nil || after != nil || unordered != nil {
+		t.Errorf("expected nil, got %v, %v and %v", before, after, unordered)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> d,
+	// a -> c -> d, d -> e, and x -> b. The nodes between a and d are a, b, c
+	// and d, while e and x are not on a path from a to d.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "d")
+	g.Edge("c", "d")
+	g.Edge("d", "e")
+	g.Edge("x", "b")
+
+	if between := g.Between("a", "d"); !reflect.DeepEqual(between, []string{"a", "b", "c", "d"}) {
+		t.Errorf("expected [a b c d], got %v", between)
+	}
+
+	if between := g.Between("b", "b"); !reflect.DeepEqual(between, []string{"b"}) {
+		t.Errorf("expected [b], got %v", between)
+	}
+
+	if between := g.Between("d", "a"); between != nil {
+		t.Errorf("expected nil, got %v", between)
+	}
+
+	if between := g.Between("a", "y"); between != nil {
+		t.Errorf("expected nil, got %v", between)
+	}
+}
+
+func TestDependencyCounts(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> d,
+	// a -> c -> d and d -> e. The node d is reached through b and c, but
+	// only counted once for a.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "d")
+	g.Edge("c", "d")
+	g.Edge("d", "e")
+
+	counts, err := g.DependencyCounts()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := map[string]int{"a": 4, "b": 2, "c": 2, "d": 1, "e": 0}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("expected %v, got %v", expected, counts)
+	}
+
+	g.Edge("e", "a")
+
+	if _, err := g.DependencyCounts(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestBlastRadii(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: app -> lib -> util,
+	// app -> util and tool -> util.
+	g.Edge("app", "lib")
+	g.Edge("lib", "util")
+	g.Edge("app", "util")
+	g.Edge("tool", "util")
+
+	radii, err := g.BlastRadii()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := map[string]int{"app": 0, "lib": 1, "tool": 0, "util": 3}
+	if !reflect.DeepEqual(radii, expected) {
+		t.Errorf("expected %v, got %v", expected, radii)
+	}
+
+	g.Edge("util", "app")
+
+	if _, err := g.BlastRadii(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestReachableCount(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> a,
+	// b -> d and e -> a. From a, the nodes b, c and d can be reached.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "a")
+	g.Edge("b", "d")
+	g.Edge("e", "a")
+
+	for k, expected := range map[string]int{"a": 3, "d": 0, "e": 4, "x": 0} {
+		if n := g.ReachableCount(k); n != expected {
+			t.Errorf("expected %v for %v, got %v", expected, k, n)
+		}
+	}
+}
+
+func TestBestRoot(t *testing.T) {
+	g := New[string]()
+
+	if k, n := g.BestRoot(); k != "" || n != 0 {
+		t.Errorf("expected no root, got %v and %v", k, n)
+	}
+
+	// We construct a graph with the following structure: a -> b -> c,
+	// x -> y, x -> z, z -> c and an isolated node e. The root x reaches
+	// three nodes.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("x", "y")
+	g.Edge("x", "z")
+	g.Edge("z", "c")
+	g.Node("e")
+
+	if k, n := g.BestRoot(); k != "x" || n != 3 {
+		t.Errorf("expected x and 3, got %v and %v", k, n)
+	}
+
+	// With equal coverage, the smallest key wins.
+	g.Edge("b", "d")
+
+	if k, n := g.BestRoot(); k != "a" || n != 3 {
+		t.Errorf("expected a and 3, got %v and %v", k, n)
+	}
+}
+
+func TestImpliedEdges(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> d,
+	// a -> c and d -> a. The node a depends on d only indirectly.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("a", "c")
+	g.Edge("d", "a")
+
+	if implied := g.ImpliedEdges("a"); !reflect.DeepEqual(implied, []string{"d"}) {
+		t.Errorf("expected [d], got %v", implied)
+	}
+
+	if implied := g.ImpliedEdges("c"); !reflect.DeepEqual(implied, []string{"a", "b"}) {
+		t.Errorf("expected [a b], got %v", implied)
+	}
+
+	if implied := g.ImpliedEdges("x"); implied != nil {
+		t.Errorf("expected nil, got %v", implied)
+	}
+}
+
+func TestReachabilityBitsets(t *testing.T) {
+	g := buildGraph(200)
+
+	// We add a cycle, so some nodes reach themselves.
+	g.Edge(150, 100)
+
+	sets, keys := g.ReachabilityBitsets()
+
+	if len(sets) != 200 || len(keys) != 200 {
+		t.Errorf("expected 200 sets and keys, got %v and %v", len(sets), len(keys))
+		return
+	}
+
+	for k := range g.nodes {
+		r := reachable(g.nodes, k)
+		for i, m := range keys {
+			if bit := sets[k].Bit(i) == 1; bit != r[m] {
+				t.Errorf("expected %v for %v reaching %v, got %v", r[m], k, m, bit)
+				return
+			}
+		}
+	}
+}
+
+func TestReachability(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c and
+	// d -> c.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("d", "c")
+
+	r := g.Reachability()
+
+	if !r.Can("a", "c") {
+		t.Error("expected a to reach c")
+	}
+	if r.Can("a", "d") || r.Can("c", "a") || r.Can("a", "a") {
+		t.Error("expected a not to reach d, c not to reach a and a not to reach itself")
+	}
+	if r.Can("x", "a") || r.Can("a", "x") {
+		t.Error("expected missing nodes not to be reached")
+	}
+
+	// Only the nodes queried from are computed, once.
+	if len(r.sets) != 2 {
+		t.Errorf("expected 2 sets, got %v", len(r.sets))
+	}
+
+	// A change to the graph discards the computed sets.
+	g.Edge("c", "a")
+
+	if !r.Can("c", "a") || !r.Can("a", "a") {
+		t.Error("expected c to reach a and a to reach itself")
+	}
+	if len(r.sets) != 2 {
+		t.Errorf("expected 2 sets, got %v", len(r.sets))
+	}
+}
+
+func TestEdgeCriticality(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> d,
+	// a -> c and x -> b. Removing b -> c disconnects b and x from c and d,
+	// while a still reaches them through a -> c.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("a", "c")
+	g.Edge("x", "b")
+
+	if n := g.EdgeCriticality("b", "c"); n != 4 {
+		t.Errorf("expected 4, got %v", n)
+	}
+
+	if n := g.EdgeCriticality("a", "c"); n != 0 {
+		t.Errorf("expected 0, got %v", n)
+	}
+
+	if n := g.EdgeCriticality("c", "d"); n != 4 {
+		t.Errorf("expected 4, got %v", n)
+	}
+
+	if n := g.EdgeCriticality("d", "a"); n != 0 {
+		t.Errorf("expected 0, got %v", n)
+	}
+
+	// Removing the edge of a cycle of two nodes loses the pairs (c, c),
+	// (d, d) and (d, c).
+	g.Edge("d", "c")
+
+	if n := g.EdgeCriticality("d", "c"); n != 3 {
+		t.Errorf("expected 3, got %v", n)
+	}
+}
+
+func TestImpactOf(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: app -> lib -> util,
+	// app -> cli, cli -> util and tool -> cli. A change to util affects
+	// everything but itself, while changes to cli and lib only affect app and
+	// tool.
+	g.Edge("app", "lib")
+	g.Edge("lib", "util")
+	g.Edge("app", "cli")
+	g.Edge("cli", "util")
+	g.Edge("tool", "cli")
+
+	expected := []string{"app", "cli", "lib", "tool"}
+	if keys := g.ImpactOf("util"); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v, got %v", expected, keys)
+	}
+
+	expected = []string{"app", "tool"}
+	if keys := g.ImpactOf("cli", "lib"); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v, got %v", expected, keys)
+	}
+
+	if keys := g.ImpactOf("app", "missing"); keys != nil {
+		t.Errorf("expected nil, got %v", keys)
+	}
+}
+
+func TestInvalidationFrontier(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: app -> lib -> util,
+	// tool -> lib, cli -> tool, test -> util and doc without edges.
+	g.Edge("app", "lib")
+	g.Edge("lib", "util")
+	g.Edge("tool", "lib")
+	g.Edge("cli", "tool")
+	g.Edge("test", "util")
+	g.Node("doc")
+
+	if keys := g.InvalidationFrontier([]string{"lib"}); !reflect.DeepEqual(keys, []string{"app", "cli"}) {
+		t.Errorf("expected [app cli], got %v", keys)
+	}
+
+	if keys := g.InvalidationFrontier([]string{"util", "tool", "doc"}); !reflect.DeepEqual(keys, []string{"app", "cli", "doc", "test"}) {
+		t.Errorf("expected [app cli doc test], got %v", keys)
+	}
+
+	// Of a cycle that nothing else depends on, only the smallest key is
+	// part of the frontier.
+	g.Edge("tool", "cli")
+
+	if keys := g.InvalidationFrontier([]string{"tool", "x"}); !reflect.DeepEqual(keys, []string{"cli"}) {
+		t.Errorf("expected [cli], got %v", keys)
+	}
+
+	if keys := g.InvalidationFrontier([]string{"x"}); keys != nil {
+		t.Errorf("expected nil, got %v", keys)
+	}
+}
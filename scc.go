@@ -0,0 +1,294 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import "slices"
+
+// SortComponents returns the strongly connected components of the graph in
+// topological order. A strongly connected component is a maximal set of nodes
+// that can all reach each other, so every cycle is within one component, and
+// the graph of components is acyclic. Like Sort, a component with an edge to
+// another component comes before it. Within a component, the nodes are in a
+// deterministic order, as they cannot be ordered topologically.
+//
+// Unlike Sort, SortComponents also orders cyclic graphs, so it never returns
+// an error. The error is part of the signature so it can replace Sort.
+func (g *Graph[Key]) SortComponents() ([][]Key, error) {
+	components := g.components()
+
+	for _, c := range components {
+		sortKeys(c)
+	}
+	slices.Reverse(components)
+
+	return components, nil
+}
+
+// SCCKosaraju returns the strongly connected components of the graph, like
+// SortComponents, but computes them with Kosaraju's algorithm instead of
+// Tarjan's. Both return the same components with their nodes in the same
+// order, and both return the components in a topological order, but that
+// order is not unique, so the components may be in a different order. Having
+// a second, simpler implementation is useful to verify the first.
+//
+// The algorithm does a depth-first search over the graph to find the order in
+// which the searches of the nodes finish, and then collects the components
+// with depth-first searches over the reversed graph, in reverse finish order.
+func (g *Graph[Key]) SCCKosaraju() [][]Key {
+	// https://en.wikipedia.org/wiki/Kosaraju%27s_algorithm
+
+	visited := make(map[Key]bool, len(g.nodes))
+	finished := make([]Key, 0, len(g.nodes))
+
+	var visit func(n Key)
+	visit = func(n Key) {
+		visited[n] = true
+		for m := range g.nodes[n] {
+			if !visited[m] {
+				visit(m)
+			}
+		}
+		finished = append(finished, n)
+	}
+
+	for k := range g.nodes {
+		if !visited[k] {
+			visit(k)
+		}
+	}
+
+	// In the reversed graph, a search from the node that finished last only
+	// reaches the nodes of its own component, and the components it reaches
+	// afterwards are the components that come later in a topological order.
+	r := g.Reverse()
+	assigned := make(map[Key]bool, len(g.nodes))
+
+	var components [][]Key
+	var collect func(n Key)
+	collect = func(n Key) {
+		assigned[n] = true
+		components[len(components)-1] = append(components[len(components)-1], n)
+		for m := range r.nodes[n] {
+			if !assigned[m] {
+				collect(m)
+			}
+		}
+	}
+
+	for _, k := range slices.Backward(finished) {
+		if !assigned[k] {
+			components = append(components, nil)
+			collect(k)
+		}
+	}
+
+	for _, c := range components {
+		sortKeys(c)
+	}
+
+	return components
+}
+
+// condensation returns the strongly connected components of the graph, using
+// Tarjan's algorithm, in reverse topological order like components, and the
+// index of the component of every node. The indexes are assigned during the
+// search, as every component is found.
+func (g *Graph[Key]) condensation() ([][]Key, map[Key]int) {
+	// https://en.wikipedia.org/wiki/Tarjan%27s_strongly_connected_components_algorithm
+
+	// The order in which nodes are visited, and the lowest index that can be
+	// reached from the subtree of a node through a single edge that is not
+	// part of the depth-first search tree.
+	index := make(map[Key]int, len(g.nodes))
+	low := make(map[Key]int, len(g.nodes))
+
+	// The nodes that have been visited but are not part of a component yet.
+	var stack []Key
+	onStack := make(map[Key]bool)
+
+	var components [][]Key
+	id := make(map[Key]int, len(g.nodes))
+
+	var visit func(n Key)
+	visit = func(n Key) {
+		index[n] = len(index)
+		low[n] = index[n]
+		stack = append(stack, n)
+		onStack[n] = true
+
+		for m := range g.nodes[n] {
+			if _, ok := index[m]; !ok {
+				visit(m)
+				low[n] = min(low[n], low[m])
+			} else if onStack[m] {
+				low[n] = min(low[n], index[m])
+			}
+		}
+
+		// If n is the first visited node of its component, all nodes above it
+		// on the stack are the component.
+		if low[n] == index[n] {
+			i := len(stack) - 1
+			for stack[i] != n {
+				i--
+			}
+			c := slices.Clone(stack[i:])
+			stack = stack[:i]
+			for _, m := range c {
+				onStack[m] = false
+				id[m] = len(components)
+			}
+			components = append(components, c)
+		}
+	}
+
+	for k := range g.nodes {
+		if _, ok := index[k]; !ok {
+			visit(k)
+		}
+	}
+
+	return components, id
+}
+
+// MinEdgesToStronglyConnect returns the minimum number of edges that have to
+// be added to make the whole graph a single strongly connected component, in
+// which every node can reach every other node. For a graph with more than one
+// component, this is the larger of the number of components without incoming
+// edges from other components and the number of components without outgoing
+// edges to other components. It returns 0 for an empty graph.
+func (g *Graph[Key]) MinEdgesToStronglyConnect() int {
+	components, id := g.condensation()
+	if len(components) <= 1 {
+		return 0
+	}
+
+	// Whether each component has an edge from or to another component.
+	hasIn := make([]bool, len(components))
+	hasOut := make([]bool, len(components))
+	for from, e := range g.nodes {
+		for to := range e {
+			if f, t := id[from], id[to]; f != t {
+				hasOut[f] = true
+				hasIn[t] = true
+			}
+		}
+	}
+
+	sources, sinks := 0, 0
+	for i := range components {
+		if !hasIn[i] {
+			sources++
+		}
+		if !hasOut[i] {
+			sinks++
+		}
+	}
+
+	return max(sources, sinks)
+}
+
+// MinimumRootCover returns a smallest set of nodes from which every node can
+// be reached, or is one of them, in a deterministic order. For an acyclic
+// graph, these are the nodes without incoming edges. For a cyclic graph, it
+// contains one node of every strongly connected component without incoming
+// edges from other components, which is the node with the smallest key, as
+// every node of such a component reaches the same nodes. It returns nil for
+// an empty graph.
+func (g *Graph[Key]) MinimumRootCover() []Key {
+	components, id := g.condensation()
+
+	// Whether each component has an edge from another component.
+	hasIn := make([]bool, len(components))
+	for from, e := range g.nodes {
+		for to := range e {
+			if f, t := id[from], id[to]; f != t {
+				hasIn[t] = true
+			}
+		}
+	}
+
+	var roots []Key
+	for i, c := range components {
+		if !hasIn[i] {
+			roots = append(roots, slices.MinFunc(c, compareKeys[Key]))
+		}
+	}
+	sortKeys(roots)
+
+	return roots
+}
+
+// components returns the strongly connected components of the graph, using
+// Tarjan's algorithm. The components are in reverse topological order: a
+// component comes after all components it has an edge to.
+func (g *Graph[Key]) components() [][]Key {
+	components, _ := g.condensation()
+	return components
+}
+
+// SCCOf returns the id of the strongly connected component of every node, so
+// that two nodes are in the same component if and only if they have the same
+// id. The ids run from 0 to the number of components minus one, and they are
+// in topological order: every edge between two components goes from a lower
+// to a higher id. Which of the components that do not depend on each other
+// gets the lower id is not specified. The ids are assigned during a single
+// search with Tarjan's algorithm.
+func (g *Graph[Key]) SCCOf() map[Key]int {
+	components, id := g.condensation()
+
+	// The components are found in reverse topological order.
+	for k, c := range id {
+		id[k] = len(components) - 1 - c
+	}
+
+	return id
+}
+
+// Ranks assigns every node a rank, such that every edge between two strongly
+// connected components goes from a lower to a higher rank, and all nodes of a
+// component share the same rank. Components without incoming edges from other
+// components have rank 0, and every other component has the smallest rank
+// that is higher than the ranks of the components with an edge to it: the
+// length of the longest path to it in the graph of components. Unlike Sort,
+// it also works on cyclic graphs, for example to lay out a graph in layers.
+func (g *Graph[Key]) Ranks() map[Key]int {
+	components, id := g.condensation()
+
+	// The components are in reverse topological order, so we visit them
+	// backwards, and every component is visited after all components with an
+	// edge to it.
+	rank := make([]int, len(components))
+	for i := len(components) - 1; i >= 0; i-- {
+		for _, from := range components[i] {
+			for to := range g.nodes[from] {
+				if c := id[to]; c != i {
+					rank[c] = max(rank[c], rank[i]+1)
+				}
+			}
+		}
+	}
+
+	ranks := make(map[Key]int, len(g.nodes))
+	for k, c := range id {
+		ranks[k] = rank[c]
+	}
+
+	return ranks
+}
+
+// SCCSizes returns the number of nodes of every strongly connected component,
+// from large to small, without the members of the components. A graph
+// without cycles has only components of size 1.
+func (g *Graph[Key]) SCCSizes() []int {
+	components := g.components()
+
+	sizes := make([]int, len(components))
+	for i, c := range components {
+		sizes[i] = len(c)
+	}
+	slices.Sort(sizes)
+	slices.Reverse(sizes)
+
+	return sizes
+}
@@ -0,0 +1,225 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSortComponents(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> b,
+	// c -> d -> e -> d and a -> f. The components are {a}, {b c}, {d e} and
+	// {f}, where {b c} comes before {d e}.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "b")
+	g.Edge("c", "d")
+	g.Edge("d", "e")
+	g.Edge("e", "d")
+	g.Edge("a", "f")
+
+	components, err := g.SortComponents()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(components) != 4 || !reflect.DeepEqual(components[0], []string{"a"}) {
+		t.Errorf("expected 4 components starting with [a], got %v", components)
+		return
+	}
+
+	pos := make(map[string]int)
+	for i, c := range components {
+		for _, k := range c {
+			pos[k] = i
+		}
+	}
+
+	if pos["b"] != pos["c"] || pos["d"] != pos["e"] || pos["b"] >= pos["d"] {
+		t.Errorf("expected [b c] before [d e], got %v", components)
+	}
+}
+
+func TestMinEdgesToStronglyConnect(t *testing.T) {
+	g := New[string]()
+
+	if n := g.MinEdgesToStronglyConnect(); n != 0 {
+		t.Errorf("expected 0 for an empty graph, got %v", n)
+	}
+
+	// We construct a graph with the following structure: a -> b -> a,
+	// a -> c, a -> d and e -> d. The components are {a b}, {c}, {d} and {e}.
+	// The sources are {a b} and {e}, and the sinks are {c} and {d}.
+	g.Edge("a", "b")
+	g.Edge("b", "a")
+	g.Edge("a", "c")
+	g.Edge("a", "d")
+	g.Edge("e", "d")
+
+	if n := g.MinEdgesToStronglyConnect(); n != 2 {
+		t.Errorf("expected 2, got %v", n)
+	}
+
+	// Adding the edges c -> e and d -> a makes the graph strongly connected.
+	g.Edge("c", "e")
+	g.Edge("d", "a")
+
+	if n := g.MinEdgesToStronglyConnect(); n != 0 {
+		t.Errorf("expected 0, got %v", n)
+	}
+
+	// An isolated node is both a source and a sink.
+	g.Node("f")
+
+	if n := g.MinEdgesToStronglyConnect(); n != 2 {
+		t.Errorf("expected 2, got %v", n)
+	}
+}
+
+func TestSCCKosaraju(t *testing.T) {
+	g := buildGraph(1000)
+
+	// We add edges back to create cycles of different sizes.
+	g.Edge(10, 0)
+	g.Edge(500, 300)
+	g.Edge(999, 998)
+
+	kosaraju := g.SCCKosaraju()
+
+	// Every edge must point to the same or a later component.
+	pos := make(map[int]int)
+	for i, c := range kosaraju {
+		for _, k := range c {
+			pos[k] = i
+		}
+	}
+
+	for from, e := range g.nodes {
+		for to := range e {
+			if pos[from] > pos[to] {
+				t.Errorf("expected %v before %v, got %v and %v", from, to, pos[from], pos[to])
+			}
+		}
+	}
+
+	// Both algorithms must find the same components.
+	tarjan, _ := g.SortComponents()
+
+	byFirst := func(a, b []int) int {
+		return a[0] - b[0]
+	}
+	slices.SortFunc(kosaraju, byFirst)
+	slices.SortFunc(tarjan, byFirst)
+
+	if !reflect.DeepEqual(kosaraju, tarjan) {
+		t.Errorf("expected %v, got %v", tarjan, kosaraju)
+	}
+}
+
+func TestSCCOf(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> a,
+	// c -> d -> e -> d and x -> e. The components are {a b c}, {d e} and
+	// {x}.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "a")
+	g.Edge("c", "d")
+	g.Edge("d", "e")
+	g.Edge("e", "d")
+	g.Edge("x", "e")
+
+	id := g.SCCOf()
+
+	if len(id) != 6 {
+		t.Errorf("expected 6 nodes, got %v", id)
+	}
+
+	if id["a"] != id["b"] || id["a"] != id["c"] || id["d"] != id["e"] {
+		t.Errorf("expected nodes of the same component to share an id, got %v", id)
+	}
+
+	if id["a"] == id["d"] || id["x"] == id["d"] || id["x"] == id["a"] {
+		t.Errorf("expected components to have different ids, got %v", id)
+	}
+
+	// Every edge between components goes to a higher id.
+	if id["c"] >= id["d"] || id["x"] >= id["e"] {
+		t.Errorf("expected ids in topological order, got %v", id)
+	}
+
+	for _, c := range id {
+		if c < 0 || c > 2 {
+			t.Errorf("expected ids from 0 to 2, got %v", id)
+		}
+	}
+}
+
+func TestMinimumRootCover(t *testing.T) {
+	g := New[string]()
+
+	if roots := g.MinimumRootCover(); roots != nil {
+		t.Errorf("expected nil, got %v", roots)
+	}
+
+	// We construct a graph with the following structure: c -> b -> c,
+	// b -> d, a -> d, d -> e -> d and an isolated node x. The cycle of b and
+	// c has no incoming edges, so one of its nodes is needed.
+	g.Edge("c", "b")
+	g.Edge("b", "c")
+	g.Edge("b", "d")
+	g.Edge("a", "d")
+	g.Edge("d", "e")
+	g.Edge("e", "d")
+	g.Node("x")
+
+	if roots := g.MinimumRootCover(); !reflect.DeepEqual(roots, []string{"a", "b", "x"}) {
+		t.Errorf("expected [a b x], got %v", roots)
+	}
+}
+
+func TestRanks(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> b,
+	// c -> d, a -> d and e -> e. The component {b c} has rank 1, so d, which
+	// can also be reached from a directly, has rank 2.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "b")
+	g.Edge("c", "d")
+	g.Edge("a", "d")
+	g.Edge("e", "e")
+
+	ranks := g.Ranks()
+	expected := map[string]int{"a": 0, "b": 1, "c": 1, "d": 2, "e": 0}
+
+	if !reflect.DeepEqual(ranks, expected) {
+		t.Errorf("expected %v, got %v", expected, ranks)
+	}
+}
+
+func TestSCCSizes(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> a,
+	// c -> d -> e -> d and f.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "a")
+	g.Edge("c", "d")
+	g.Edge("d", "e")
+	g.Edge("e", "d")
+	g.Node("f")
+
+	if sizes := g.SCCSizes(); !reflect.DeepEqual(sizes, []int{3, 2, 1}) {
+		t.Errorf("expected [3 2 1], got %v", sizes)
+	}
+}
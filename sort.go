@@ -0,0 +1,592 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"container/heap"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+)
+
+// progressSteps is the number of times SortWithProgress reports progress
+// during a sort, not counting the final report. It bounds the callback
+// overhead regardless of the size of the graph.
+const progressSteps = 100
+
+// SortWithProgress is like Sort, but calls onProgress periodically with the
+// number of nodes emitted so far and the total number of nodes. It reports
+// progress about once per percent of the nodes, and once more when the sort
+// is finished, so the overhead of onProgress does not depend on the size of
+// the graph. When the graph has a cycle, the final report has done < total.
+func (g *Graph[Key]) SortWithProgress(onProgress func(done, total int)) ([]Key, error) {
+	total := len(g.nodes)
+
+	// We report progress every step nodes, rounded up so there are at most
+	// progressSteps reports before the final one. For small graphs this means
+	// we report progress for every node.
+	step := max((total+progressSteps-1)/progressSteps, 1)
+
+	var sorted []Key
+
+	ok := g.kahn(func(n Key) bool {
+		sorted = append(sorted, n)
+		if len(sorted)%step == 0 && len(sorted) < total {
+			onProgress(len(sorted), total)
+		}
+		return true
+	})
+
+	onProgress(len(sorted), total)
+
+	if !ok {
+		return nil, ErrCycle
+	}
+
+	return sorted, nil
+}
+
+// SortDependenciesFirst is like Sort, but reads an edge a -> b as "a depends
+// on b", so b comes before a. Add(node, deps) adds an edge from the node to
+// each of its dependencies, so this is the order in which dependencies have to
+// be built or started: every node comes after all nodes it depends on. It is
+// the reverse of the order of Sort, and returns ErrCycle if the graph has a
+// cycle.
+func (g *Graph[Key]) SortDependenciesFirst() ([]Key, error) {
+	sorted, err := g.Sort()
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(sorted)
+
+	return sorted, nil
+}
+
+// TopologicalRanks returns the position of every node in a topological order
+// of the graph. If b can be reached from a, the rank of a is lower than the
+// rank of b, so a rank of a that is not lower than the rank of b proves in
+// O(1) time that b cannot be reached from a, before doing a more expensive
+// search. A lower rank does not imply that b can be reached. It returns
+// ErrCycle if the graph has a cycle.
+func (g *Graph[Key]) TopologicalRanks() (map[Key]int, error) {
+	ranks := make(map[Key]int, len(g.nodes))
+
+	if !g.kahn(func(n Key) bool {
+		ranks[n] = len(ranks)
+		return true
+	}) {
+		return nil, ErrCycle
+	}
+
+	return ranks, nil
+}
+
+// SortPartial is like Sort, but when the graph has a cycle it still returns
+// the nodes it could sort, in topological order, together with the remaining
+// nodes and ErrCycle. The remaining nodes are the nodes on a cycle and the
+// nodes that can only be reached through a cycle, in arbitrary order. For an
+// acyclic graph, remaining is empty and err is nil.
+func (g *Graph[Key]) SortPartial() (sorted []Key, remaining []Key, err error) {
+	done := make(map[Key]bool, len(g.nodes))
+
+	if g.kahn(func(n Key) bool {
+		sorted = append(sorted, n)
+		done[n] = true
+		return true
+	}) {
+		return sorted, nil, nil
+	}
+
+	for k := range g.nodes {
+		if !done[k] {
+			remaining = append(remaining, k)
+		}
+	}
+
+	return sorted, remaining, ErrCycle
+}
+
+// SortedSeq returns an iterator over the nodes of the graph in topological
+// order, like Sort, without building a list of all nodes. It returns ErrCycle
+// before iterating if the graph has a cycle, which it detects with a first
+// pass over the graph that does not store the order. Every iteration sorts
+// the graph again, so the iterator reflects changes made to the graph after
+// SortedSeq returned, but it must not be used while the graph changes.
+func (g *Graph[Key]) SortedSeq() (iter.Seq[Key], error) {
+	if !g.kahn(func(Key) bool { return true }) {
+		return nil, ErrCycle
+	}
+
+	return func(yield func(Key) bool) {
+		g.kahn(yield)
+	}, nil
+}
+
+// WriteSorted writes the nodes of the graph to w in topological order, like
+// Sort, formatted with fmt's %v verb and separated by sep. Every node is
+// written as soon as it is sorted, without building a list of all nodes, see
+// SortedSeq. It returns ErrCycle before writing anything if the graph has a
+// cycle, and stops at the first error returned by w. Nothing is written after
+// the last node, so a trailing newline has to be written separately.
+func (g *Graph[Key]) WriteSorted(w io.Writer, sep string) error {
+	seq, err := g.SortedSeq()
+	if err != nil {
+		return err
+	}
+
+	first := true
+	for k := range seq {
+		if !first {
+			if _, err := io.WriteString(w, sep); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		if _, err := fmt.Fprint(w, k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WalkLayers calls visit for every layer of the graph, in topological order.
+// The first layer are the nodes without incoming edges, and every next layer
+// are the nodes whose incoming edges all come from earlier layers. The nodes
+// within a layer do not depend on each other, so they can be processed in
+// parallel, and a layer is only computed after visit returned for the previous
+// one. The nodes of a layer are in a deterministic order, and visit may keep
+// the slice. WalkLayers stops and returns the error if visit returns an error.
+// It returns ErrCycle before calling visit if the graph has a cycle.
+func (g *Graph[Key]) WalkLayers(visit func(layer []Key) error) error {
+	if !g.kahn(func(Key) bool { return true }) {
+		return ErrCycle
+	}
+
+	indegree := g.InDegrees()
+
+	var layer []Key
+	for k := range g.nodes {
+		if indegree[k] == 0 {
+			layer = append(layer, k)
+		}
+	}
+
+	for len(layer) > 0 {
+		sortKeys(layer)
+		if err := visit(layer); err != nil {
+			return err
+		}
+
+		var next []Key
+		for _, n := range layer {
+			for m := range g.nodes[n] {
+				indegree[m]--
+				if indegree[m] == 0 {
+					next = append(next, m)
+				}
+			}
+		}
+		layer = next
+	}
+
+	return nil
+}
+
+// TopologicalOrders returns an iterator over all topological orders of the
+// graph. The orders are generated lazily by backtracking, choosing the ready
+// nodes in a deterministic order, so iteration can stop as soon as a suitable
+// order is found. A graph can have a number of orders that is exponential in
+// its number of nodes. Every yielded slice is a new slice, which the caller
+// may keep. The iterator yields nothing if the graph has a cycle, and a single
+// empty order for an empty graph. It must not be used while the graph
+// changes.
+func (g *Graph[Key]) TopologicalOrders() iter.Seq[[]Key] {
+	return func(yield func([]Key) bool) {
+		if !g.kahn(func(Key) bool { return true }) {
+			return
+		}
+
+		keys := g.keys()
+
+		indegree := g.InDegrees()
+
+		used := make(map[Key]bool, len(g.nodes))
+		order := make([]Key, 0, len(g.nodes))
+
+		// extend tries every ready node as the next node of the order, and
+		// returns false when iteration has to stop.
+		var extend func() bool
+		extend = func() bool {
+			if len(order) == len(keys) {
+				return yield(slices.Clone(order))
+			}
+
+			for _, k := range keys {
+				if used[k] || indegree[k] > 0 {
+					continue
+				}
+
+				used[k] = true
+				order = append(order, k)
+				for m := range g.nodes[k] {
+					indegree[m]--
+				}
+
+				ok := extend()
+
+				for m := range g.nodes[k] {
+					indegree[m]++
+				}
+				order = order[:len(order)-1]
+				used[k] = false
+
+				if !ok {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		extend()
+	}
+}
+
+// ReverseTopologicalSeq is like SortedSeq, but iterates over the nodes in
+// reverse topological order: every node comes after all nodes it has an edge
+// to. When an edge means "depends on", this is the order in which resources
+// can be torn down safely. It returns ErrCycle before iterating if the graph
+// has a cycle, and it must not be used while the graph changes.
+//
+// Every iteration is a depth-first search over the edges that yields every
+// node when the search of its edges finishes, so it only keeps the set of
+// visited nodes and the current path, and stops as soon as the loop does.
+func (g *Graph[Key]) ReverseTopologicalSeq() (iter.Seq[Key], error) {
+	if !g.kahn(func(Key) bool { return true }) {
+		return nil, ErrCycle
+	}
+
+	return func(yield func(Key) bool) {
+		visited := make(map[Key]bool, len(g.nodes))
+
+		var visit func(n Key) bool
+		visit = func(n Key) bool {
+			visited[n] = true
+			for m := range g.nodes[n] {
+				if !visited[m] && !visit(m) {
+					return false
+				}
+			}
+			return yield(n)
+		}
+
+		for k := range g.nodes {
+			if !visited[k] && !visit(k) {
+				return
+			}
+		}
+	}, nil
+}
+
+// SortByOutDegree is like Sort, but whenever multiple nodes are ready to be
+// emitted, it emits them in order of their out-degree: the nodes with the
+// most outgoing edges first if descending is true, and the nodes with the
+// fewest outgoing edges first otherwise. The order among nodes with the same
+// out-degree is arbitrary.
+func (g *Graph[Key]) SortByOutDegree(descending bool) ([]Key, error) {
+	return g.sortFunc(func(a, b Key) bool {
+		if descending {
+			return len(g.nodes[a]) > len(g.nodes[b])
+		}
+		return len(g.nodes[a]) < len(g.nodes[b])
+	})
+}
+
+// SortByPriority is like Sort, but whenever multiple nodes are ready to be
+// emitted, it emits the node with the highest priority first. The ready nodes
+// are kept in a max-heap. The order among nodes with the same priority is
+// arbitrary. The priority function is called once for every node.
+func (g *Graph[Key]) SortByPriority(priority func(Key) int) ([]Key, error) {
+	p := make(map[Key]int, len(g.nodes))
+	for k := range g.nodes {
+		p[k] = priority(k)
+	}
+
+	return g.sortFunc(func(a, b Key) bool {
+		return p[a] > p[b]
+	})
+}
+
+// SortSeeded is like Sort, but whenever multiple nodes are ready to be
+// emitted, it emits them in their relative order in seed. Nodes that are not
+// in seed are emitted after the nodes that are, in arbitrary order. Passing
+// the result of a previous sort as seed keeps the order stable between runs,
+// without requiring an ordering of the keys. Keys in seed that are not nodes
+// of the graph are ignored.
+func (g *Graph[Key]) SortSeeded(seed []Key) ([]Key, error) {
+	rank := make(map[Key]int, len(seed))
+	for i, k := range seed {
+		k = g.key(k)
+		if _, ok := rank[k]; !ok {
+			rank[k] = i
+		}
+	}
+
+	return g.sortFunc(func(a, b Key) bool {
+		ra, oka := rank[a]
+		rb, okb := rank[b]
+		if oka && okb {
+			return ra < rb
+		}
+		return oka
+	})
+}
+
+// SortWithHints is like Sort, but also follows the hints where the edges of
+// the graph leave a choice: every hint [a, b] asks for a to come before b,
+// like an edge a -> b would, without being one. A hint that contradicts the
+// edges, or the hints before it, is ignored, so earlier hints take precedence
+// over later ones. Hints with keys that are not nodes of the graph are ignored
+// too. It only returns ErrCycle if the graph itself has a cycle.
+//
+// The hints are added one by one as edges to a copy of the graph with
+// EdgeChecked, which sorts the copy once and then repairs its order for every
+// hint, so the graph is only sorted once.
+func (g *Graph[Key]) SortWithHints(hints [][2]Key) ([]Key, error) {
+	c := g.Copy()
+	for _, h := range hints {
+		from, to := g.key(h[0]), g.key(h[1])
+		if from == to {
+			continue
+		}
+		if _, ok := g.nodes[from]; !ok {
+			continue
+		}
+		if _, ok := g.nodes[to]; !ok {
+			continue
+		}
+
+		// A hint that would create a cycle is rejected and ignored. If the
+		// copy has no order after an error, the graph itself has a cycle.
+		if err := c.EdgeChecked(from, to); err != nil && c.pos == nil {
+			return nil, err
+		}
+	}
+
+	// Without any hint to check, the copy has not been sorted yet.
+	if c.pos == nil {
+		return c.Sort()
+	}
+
+	return c.order, nil
+}
+
+// SortTargets is like Sort, but only sorts the given targets and the nodes
+// that can be reached from them, which are the nodes the targets depend on. It
+// returns an error wrapping ErrNotFound if one of the targets does not exist,
+// and ErrCycle if the targets or their dependencies have a cycle. Cycles
+// elsewhere in the graph do not matter.
+func (g *Graph[Key]) SortTargets(targets ...Key) ([]Key, error) {
+	targets = slices.Clone(targets)
+	for i, t := range targets {
+		t = g.key(t)
+		targets[i] = t
+		if _, ok := g.nodes[t]; !ok {
+			return nil, fmt.Errorf("%w: %v", ErrNotFound, t)
+		}
+	}
+
+	keep := reachable(g.nodes, targets...)
+	for _, t := range targets {
+		keep[t] = true
+	}
+
+	return g.subgraph(keep).Sort()
+}
+
+// SortSplit is like Sort, but splits the order in two phases at pivot: before
+// are the nodes from which pivot can be reached, which must come before it,
+// followed by pivot itself; after are all other nodes. Both are in
+// topological order, and so is before followed by after, so before can run as
+// a first phase that reaches pivot, and after as a second phase. It returns an
+// error wrapping ErrNotFound if pivot does not exist, and ErrCycle if the
+// graph has a cycle.
+func (g *Graph[Key]) SortSplit(pivot Key) (before []Key, after []Key, err error) {
+	pivot = g.key(pivot)
+	if _, ok := g.nodes[pivot]; !ok {
+		return nil, nil, fmt.Errorf("%w: %v", ErrNotFound, pivot)
+	}
+
+	sorted, err := g.Sort()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ancestors := reachable(g.Reverse().nodes, pivot)
+	ancestors[pivot] = true
+
+	for _, k := range sorted {
+		if ancestors[k] {
+			before = append(before, k)
+		} else {
+			after = append(after, k)
+		}
+	}
+
+	return before, after, nil
+}
+
+// sortFunc is like Sort, but whenever multiple nodes are ready to be emitted,
+// it emits the node that is first according to less. It keeps the ready nodes
+// in a heap, which adds a factor log(n) to the time complexity of Sort.
+func (g *Graph[Key]) sortFunc(less func(a, b Key) bool) ([]Key, error) {
+	// We count the incoming edges of every node. A node is ready when all
+	// nodes with an edge to it have been emitted.
+	indegree := g.InDegrees()
+
+	ready := &keyHeap[Key]{less: less}
+	for k := range g.nodes {
+		if indegree[k] == 0 {
+			ready.keys = append(ready.keys, k)
+		}
+	}
+	heap.Init(ready)
+
+	var sorted []Key
+	for ready.Len() > 0 {
+		n := heap.Pop(ready).(Key)
+		sorted = append(sorted, n)
+
+		for m := range g.nodes[n] {
+			indegree[m]--
+			if indegree[m] == 0 {
+				heap.Push(ready, m)
+			}
+		}
+	}
+
+	// If not all nodes have been emitted, the remaining nodes are part of or
+	// depend on a cycle.
+	if len(sorted) != len(g.nodes) {
+		return nil, ErrCycle
+	}
+
+	return sorted, nil
+}
+
+// keyHeap is a heap of keys, ordered by less. It implements heap.Interface.
+type keyHeap[Key comparable] struct {
+	keys []Key
+	less func(a, b Key) bool
+}
+
+func (h *keyHeap[Key]) Len() int           { return len(h.keys) }
+func (h *keyHeap[Key]) Less(i, j int) bool { return h.less(h.keys[i], h.keys[j]) }
+func (h *keyHeap[Key]) Swap(i, j int)      { h.keys[i], h.keys[j] = h.keys[j], h.keys[i] }
+func (h *keyHeap[Key]) Push(x any)         { h.keys = append(h.keys, x.(Key)) }
+
+func (h *keyHeap[Key]) Pop() any {
+	k := h.keys[len(h.keys)-1]
+	h.keys = h.keys[:len(h.keys)-1]
+	return k
+}
+
+// HasUniqueTopologicalOrder reports whether the graph has exactly one
+// topological order, so Sort always returns the same order. That is the case
+// if and only if every node in a topological order has an edge to the next
+// node, which means there is a path through all nodes. It returns ErrCycle if
+// the graph has a cycle.
+func (g *Graph[Key]) HasUniqueTopologicalOrder() (bool, error) {
+	sorted, err := g.Sort()
+	if err != nil {
+		return false, err
+	}
+
+	for i := 1; i < len(sorted); i++ {
+		if !g.nodes[sorted[i-1]][sorted[i]] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// IsTotalOrder reports whether every two distinct nodes are ordered by the
+// graph: one of them can be reached from the other, see Comparable. Then the
+// nodes form a single chain, apart from edges that skip over nodes of the
+// chain, and nothing can be processed in parallel. A graph is a total order
+// if and only if it has exactly one topological order, so IsTotalOrder is the
+// same check as HasUniqueTopologicalOrder, under the name of the property it
+// asserts. It returns ErrCycle if the graph has a cycle.
+func (g *Graph[Key]) IsTotalOrder() (bool, error) {
+	return g.HasUniqueTopologicalOrder()
+}
+
+// IsValidOrder reports whether order is a topological order of the graph: it
+// contains every node exactly once, and every edge points from a node to a
+// node later in the order. It takes O(n) time for n = [number of nodes] +
+// [number of edges].
+func (g *Graph[Key]) IsValidOrder(order []Key) bool {
+	if len(order) != len(g.nodes) {
+		return false
+	}
+
+	pos := make(map[Key]int, len(order))
+	for i, k := range order {
+		k = g.key(k)
+		if _, ok := g.nodes[k]; !ok {
+			return false
+		}
+		if _, ok := pos[k]; ok {
+			return false
+		}
+		pos[k] = i
+	}
+
+	for from, e := range g.nodes {
+		for to := range e {
+			if pos[from] >= pos[to] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// BackEdgeCount returns the number of edges that point from a node to the same
+// or an earlier node in order, including self-loops, as a measure of how far
+// order is from a topological order, for example to compare heuristic orders
+// of a cyclic graph. Edges from or to a node that is not in order are not
+// counted, and a node that is in order more than once counts at its first
+// position. For an order with every node exactly once, it returns 0 if and only
+// if IsValidOrder returns true. It takes O(n) time for n = [number of nodes] +
+// [number of edges].
+func (g *Graph[Key]) BackEdgeCount(order []Key) int {
+	pos := make(map[Key]int, len(order))
+	for i, k := range order {
+		k = g.key(k)
+		if _, ok := pos[k]; !ok {
+			pos[k] = i
+		}
+	}
+
+	count := 0
+	for from, e := range g.nodes {
+		f, ok := pos[from]
+		if !ok {
+			continue
+		}
+		for to := range e {
+			if t, ok := pos[to]; ok && t <= f {
+				count++
+			}
+		}
+	}
+
+	return count
+}
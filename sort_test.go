@@ -0,0 +1,717 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSortWithProgress(t *testing.T) {
+	// A graph of 199 nodes checks that the step between reports is rounded
+	// up, so there are no more reports than for 1000 nodes.
+	for _, n := range []int{1000, 199} {
+		g := buildGraph(n)
+
+		// We record every progress report, so we can check that the reports
+		// are increasing, bounded in number and end with the total.
+		var reports []int
+		keys, err := g.SortWithProgress(func(done, total int) {
+			if total != n {
+				t.Errorf("expected total %v, got %v", n, total)
+			}
+			reports = append(reports, done)
+		})
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if len(keys) != n {
+			t.Errorf("expected %v keys, got %v", n, len(keys))
+		}
+
+		if len(reports) > progressSteps+1 {
+			t.Errorf("expected at most %v reports, got %v", progressSteps+1, len(reports))
+		}
+
+		for i := 1; i < len(reports); i++ {
+			if reports[i] <= reports[i-1] {
+				t.Errorf("expected increasing reports, got %v", reports)
+				return
+			}
+		}
+
+		if reports[len(reports)-1] != n {
+			t.Errorf("expected final report %v, got %v", n, reports[len(reports)-1])
+		}
+	}
+}
+
+func TestSortWithProgressCycle(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> b.
+	// Only a can be sorted, so the final report should be 1 of 3.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "b")
+
+	var done, total int
+	_, err := g.SortWithProgress(func(d, t int) {
+		done, total = d, t
+	})
+
+	if !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+
+	if done != 1 || total != 3 {
+		t.Errorf("expected final report 1 of 3, got %v of %v", done, total)
+	}
+}
+
+func TestSortByOutDegree(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with three independent roots: a with two outgoing
+	// edges, b with one and c with none. Descending, a is emitted first and c
+	// is emitted after b, and vice versa.
+	g.Edge("a", "x")
+	g.Edge("a", "y")
+	g.Edge("b", "z")
+	g.Node("c")
+
+	keys, err := g.SortByOutDegree(true)
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	checkOrder(t, g, keys)
+
+	if keys[0] != "a" || slices.Index(keys, "b") > slices.Index(keys, "c") {
+		t.Errorf("expected a first and b before c, got %v", keys)
+	}
+
+	keys, err = g.SortByOutDegree(false)
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	checkOrder(t, g, keys)
+
+	if keys[0] != "c" || slices.Index(keys, "b") > slices.Index(keys, "a") {
+		t.Errorf("expected c first and b before a, got %v", keys)
+	}
+
+	g.Edge("z", "b")
+
+	if _, err := g.SortByOutDegree(true); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestSortWithHints(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c, and
+	// d and e without edges.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Node("d")
+	g.Node("e")
+
+	// The hint c before a contradicts the edges, and the hint d before e
+	// contradicts the earlier hint e before d, so both are ignored. The hint
+	// with x is ignored as x is not a node.
+	hints := [][2]string{
+		{"c", "e"},
+		{"e", "d"},
+		{"c", "a"},
+		{"d", "e"},
+		{"x", "a"},
+	}
+
+	keys, err := g.SortWithHints(hints)
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c", "e", "d"}) {
+		t.Errorf("expected [a b c e d], got %v", keys)
+	}
+
+	// The hints do not become edges of the graph.
+	if _, ok := g.nodes["x"]; ok || g.nodes["c"]["e"] {
+		t.Errorf("expected the graph not to change")
+	}
+
+	// Hints that are all ignored leave the order to Sort.
+	keys, err = g.SortWithHints([][2]string{{"a", "a"}, {"x", "y"}})
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	checkOrder(t, g, keys)
+
+	g.Edge("c", "a")
+
+	if _, err := g.SortWithHints(hints); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+
+	if _, err := g.SortWithHints(nil); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestSortTargets(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c,
+	// d -> c and a cycle e -> f -> e. Sorting target b gives [b c], and the
+	// cycle does not matter.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("d", "c")
+	g.Edge("e", "f")
+	g.Edge("f", "e")
+
+	keys, err := g.SortTargets("b")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !reflect.DeepEqual(keys, []string{"b", "c"}) {
+		t.Errorf("expected [b c], got %v", keys)
+	}
+
+	keys, err = g.SortTargets("a", "d")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(keys) != 4 || keys[len(keys)-1] != "c" {
+		t.Errorf("expected 4 keys ending with c, got %v", keys)
+	}
+
+	if _, err := g.SortTargets("e"); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+
+	if _, err := g.SortTargets("x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSortPartial(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> b,
+	// c -> d, and a -> e. The nodes a and e can be sorted, while b, c and d
+	// are stuck behind the cycle.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "b")
+	g.Edge("c", "d")
+	g.Edge("a", "e")
+
+	sorted, remaining, err := g.SortPartial()
+
+	if !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+
+	if !reflect.DeepEqual(sorted, []string{"a", "e"}) {
+		t.Errorf("expected [a e], got %v", sorted)
+	}
+
+	slices.Sort(remaining)
+	if !reflect.DeepEqual(remaining, []string{"b", "c", "d"}) {
+		t.Errorf("expected [b c d], got %v", remaining)
+	}
+
+	delete(g.nodes["c"], "b")
+
+	sorted, remaining, err = g.SortPartial()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(sorted) != 5 || len(remaining) != 0 {
+		t.Errorf("expected 5 sorted and 0 remaining, got %v and %v", sorted, remaining)
+	}
+}
+
+func TestSortByPriority(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, c -> d.
+	// Of the ready nodes a and c, a has the highest priority. As d has the
+	// highest priority of all, it is emitted as soon as c made it ready.
+	g.Edge("a", "b")
+	g.Edge("c", "d")
+
+	priorities := map[string]int{"a": 2, "b": 1, "c": 1, "d": 3}
+
+	keys, err := g.SortByPriority(func(k string) int {
+		return priorities[k]
+	})
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if keys[0] != "a" || slices.Index(keys, "d") != slices.Index(keys, "c")+1 {
+		t.Errorf("expected a first and d directly after c, got %v", keys)
+	}
+
+	checkOrder(t, g, keys)
+}
+
+func TestIsTotalOrder(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> d and
+	// a -> d. The edge a -> d skips over b and c.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("a", "d")
+
+	if total, err := g.IsTotalOrder(); err != nil || !total {
+		t.Errorf("expected a total order, got %v and %v", total, err)
+	}
+
+	// A branch from b makes c and e unordered.
+	g.Edge("b", "e")
+
+	if total, err := g.IsTotalOrder(); err != nil || total {
+		t.Errorf("expected no total order, got %v and %v", total, err)
+	}
+
+	g.Edge("d", "a")
+
+	if _, err := g.IsTotalOrder(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestIsValidOrder(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, a -> c.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+
+	for _, c := range []struct {
+		order []string
+		valid bool
+	}{
+		{[]string{"a", "b", "c"}, true},
+		{[]string{"a", "c", "b"}, true},
+		{[]string{"b", "a", "c"}, false},
+		{[]string{"a", "b"}, false},
+		{[]string{"a", "b", "b"}, false},
+		{[]string{"a", "b", "x"}, false},
+		{[]string{"a", "b", "c", "d"}, false},
+	} {
+		if valid := g.IsValidOrder(c.order); valid != c.valid {
+			t.Errorf("expected %v for %v, got %v", c.valid, c.order, valid)
+		}
+	}
+}
+
+func TestBackEdgeCount(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c,
+	// a -> c, c -> a and d -> d.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("a", "c")
+	g.Edge("c", "a")
+	g.Edge("d", "d")
+
+	if n := g.BackEdgeCount([]string{"a", "b", "c", "d"}); n != 2 {
+		t.Errorf("expected 2, got %v", n)
+	}
+
+	if n := g.BackEdgeCount([]string{"c", "b", "a", "d"}); n != 4 {
+		t.Errorf("expected 4, got %v", n)
+	}
+
+	// Edges of nodes that are not in the order are not counted.
+	if n := g.BackEdgeCount([]string{"a", "b"}); n != 0 {
+		t.Errorf("expected 0, got %v", n)
+	}
+}
+
+func TestSortedSeq(t *testing.T) {
+	g := buildGraph(1000)
+
+	seq, err := g.SortedSeq()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	checkOrder(t, g, slices.Collect(seq))
+
+	// Stopping early must be possible.
+	n := 0
+	for range seq {
+		n++
+		if n == 10 {
+			break
+		}
+	}
+
+	g.Edge(999, 0)
+	g.Edge(0, 999)
+
+	if _, err := g.SortedSeq(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestWriteSorted(t *testing.T) {
+	g := New[int]()
+	g.Edge(3, 2)
+	g.Edge(2, 1)
+
+	var b bytes.Buffer
+	if err := g.WriteSorted(&b, "\n"); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if expected := "3\n2\n1"; b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+
+	g.Edge(1, 3)
+	b.Reset()
+
+	if err := g.WriteSorted(&b, "\n"); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+
+	if b.Len() != 0 {
+		t.Errorf("expected nothing to be written, got %q", b.String())
+	}
+}
+
+func TestSortSplit(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> p -> c,
+	// x -> p and y -> c. The nodes a, b and x must come before p.
+	g.Edge("a", "b")
+	g.Edge("b", "p")
+	g.Edge("p", "c")
+	g.Edge("x", "p")
+	g.Edge("y", "c")
+
+	before, after, err := g.SortSplit("p")
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(before) != 4 || before[3] != "p" {
+		t.Errorf("expected 4 nodes ending with p, got %v", before)
+	}
+
+	if !reflect.DeepEqual(after, []string{"y", "c"}) {
+		t.Errorf("expected [y c], got %v", after)
+	}
+
+	checkOrder(t, g, append(before, after...))
+
+	if _, _, err := g.SortSplit("z"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	g.Edge("c", "a")
+
+	if _, _, err := g.SortSplit("p"); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestSortSeeded(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, c -> d, and
+	// e without edges. The seed orders the ready nodes, and e, which is not
+	// part of the seed, comes after them.
+	g.Edge("a", "b")
+	g.Edge("c", "d")
+	g.Node("e")
+
+	keys, err := g.SortSeeded([]string{"c", "d", "a", "x", "b"})
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !reflect.DeepEqual(keys, []string{"c", "d", "a", "b", "e"}) {
+		t.Errorf("expected [c d a b e], got %v", keys)
+	}
+
+	// The seed cannot override the edges of the graph.
+	keys, err = g.SortSeeded([]string{"e", "b", "a", "d", "c"})
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !reflect.DeepEqual(keys, []string{"e", "a", "b", "c", "d"}) {
+		t.Errorf("expected [e a b c d], got %v", keys)
+	}
+
+	g.Edge("b", "a")
+
+	if _, err := g.SortSeeded(nil); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestTopologicalOrders(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, a -> c.
+	// Both b and c can come first after a, which gives two orders.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+
+	orders := slices.Collect(g.TopologicalOrders())
+	expected := [][]string{{"a", "b", "c"}, {"a", "c", "b"}}
+
+	if !reflect.DeepEqual(orders, expected) {
+		t.Errorf("expected %v, got %v", expected, orders)
+	}
+
+	// Four independent nodes have 4! orders, and stopping early must be
+	// possible.
+	h := New[int]()
+	for i := range 4 {
+		h.Node(i)
+	}
+
+	n := 0
+	for order := range h.TopologicalOrders() {
+		checkOrder(t, h, order)
+		n++
+	}
+
+	if n != 24 {
+		t.Errorf("expected 24 orders, got %v", n)
+	}
+
+	n = 0
+	for range h.TopologicalOrders() {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+
+	g.Edge("c", "a")
+
+	for order := range g.TopologicalOrders() {
+		t.Errorf("expected no orders, got %v", order)
+	}
+}
+
+func TestWalkLayers(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> d,
+	// c -> d and a -> d. The layers are [a c], [b] and [d].
+	g.Edge("a", "b")
+	g.Edge("b", "d")
+	g.Edge("c", "d")
+	g.Edge("a", "d")
+
+	var layers [][]string
+	err := g.WalkLayers(func(layer []string) error {
+		layers = append(layers, layer)
+		return nil
+	})
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := [][]string{{"a", "c"}, {"b"}, {"d"}}
+	if !reflect.DeepEqual(layers, expected) {
+		t.Errorf("expected %v, got %v", expected, layers)
+	}
+
+	// An error stops the walk.
+	stop := errors.New("stop")
+	n := 0
+	err = g.WalkLayers(func(layer []string) error {
+		n++
+		return stop
+	})
+
+	if err != stop || n != 1 {
+		t.Errorf("expected stop after 1 layer, got %v after %v", err, n)
+	}
+
+	g.Edge("d", "a")
+
+	err = g.WalkLayers(func(layer []string) error {
+		t.Errorf("expected no layers, got %v", layer)
+		return nil
+	})
+
+	if !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestSortDependenciesFirst(t *testing.T) {
+	g := New[string]()
+
+	// We use the graph of the README: b depends on c, which depends on a.
+	g.Add("b", []string{"c"})
+	g.Add("c", []string{"a"})
+
+	keys, err := g.SortDependenciesFirst()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !reflect.DeepEqual(keys, []string{"a", "c", "b"}) {
+		t.Errorf("expected [a c b], got %v", keys)
+	}
+
+	g.Add("a", []string{"b"})
+
+	if _, err := g.SortDependenciesFirst(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestTopologicalRanks(t *testing.T) {
+	g := buildGraph(100)
+
+	ranks, err := g.TopologicalRanks()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(ranks) != 100 {
+		t.Errorf("expected 100 ranks, got %v", len(ranks))
+	}
+
+	// Every node that can be reached from a node must have a higher rank.
+	for k := range g.nodes {
+		for m := range reachable(g.nodes, k) {
+			if ranks[k] >= ranks[m] {
+				t.Errorf("expected rank of %v below rank of %v, got %v and %v", k, m, ranks[k], ranks[m])
+			}
+		}
+	}
+
+	g.Edge(99, 0)
+	g.Edge(0, 99)
+
+	if _, err := g.TopologicalRanks(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestReverseTopologicalSeq(t *testing.T) {
+	g := buildGraph(1000)
+
+	seq, err := g.ReverseTopologicalSeq()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	keys := slices.Collect(seq)
+	slices.Reverse(keys)
+	checkOrder(t, g, keys)
+
+	// Stopping early yields no more nodes.
+	n := 0
+	for range seq {
+		n++
+		if n == 10 {
+			break
+		}
+	}
+
+	if n != 10 {
+		t.Errorf("expected 10 nodes, got %v", n)
+	}
+
+	g.Edge(999, 0)
+	g.Edge(0, 999)
+
+	if _, err := g.ReverseTopologicalSeq(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestHasUniqueTopologicalOrder(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c and
+	// a -> c. The only order is [a b c].
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("a", "c")
+
+	if unique, err := g.HasUniqueTopologicalOrder(); err != nil || !unique {
+		t.Errorf("expected a unique order, got %v and %v", unique, err)
+	}
+
+	// The node d can be placed anywhere.
+	g.Node("d")
+
+	if unique, err := g.HasUniqueTopologicalOrder(); err != nil || unique {
+		t.Errorf("expected no unique order, got %v and %v", unique, err)
+	}
+
+	g.Edge("c", "a")
+
+	if _, err := g.HasUniqueTopologicalOrder(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import "slices"
+
+// Diamonds returns the diamond dependencies in the graph, as tuples of (top,
+// left, right, bottom). In a diamond, left and right both have an edge to
+// bottom, neither of them can reach the other, and top is a nearest common
+// ancestor of left and right: top can reach both, but none of the nodes it
+// has an edge to can. This means top reaches bottom through both left and
+// right. Every diamond is reported once, with left before right in the order
+// of keys, and the diamonds are sorted by top, left, right and then bottom,
+// so equal graphs give equal results.
+//
+// Diamonds is intended for acyclic graphs. It computes the ancestors of every
+// node with an in-degree of at least two, which takes O(n) time for each of
+// them, for n = [number of nodes] + [number of edges].
+func (g *Graph[Key]) Diamonds() [][4]Key {
+	r := g.Reverse()
+
+	// We cache the ancestors of every node we look at, as the same node is
+	// usually a predecessor of multiple nodes.
+	ancestors := make(map[Key]map[Key]bool)
+	ancestorsOf := func(k Key) map[Key]bool {
+		a, ok := ancestors[k]
+		if !ok {
+			a = reachable(r.nodes, k)
+			ancestors[k] = a
+		}
+		return a
+	}
+
+	var diamonds [][4]Key
+	for bottom, preds := range r.nodes {
+		if len(preds) < 2 {
+			continue
+		}
+
+		var p []Key
+		for k := range preds {
+			if k != bottom {
+				p = append(p, k)
+			}
+		}
+		sortKeys(p)
+
+		for i, left := range p {
+			for _, right := range p[i+1:] {
+				la, ra := ancestorsOf(left), ancestorsOf(right)
+
+				// If one of the predecessors can reach the other, the paths
+				// through them are not independent.
+				if la[right] || ra[left] {
+					continue
+				}
+
+				// The common ancestors of left and right.
+				common := make(map[Key]bool)
+				for k := range la {
+					if ra[k] {
+						common[k] = true
+					}
+				}
+
+				// The nearest common ancestors are the ones that do not have
+				// an edge to another common ancestor.
+				for top := range common {
+					if top == bottom {
+						continue
+					}
+
+					nearest := true
+					for m := range g.nodes[top] {
+						if common[m] {
+							nearest = false
+							break
+						}
+					}
+
+					if nearest {
+						diamonds = append(diamonds, [4]Key{top, left, right, bottom})
+					}
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(diamonds, func(a, b [4]Key) int {
+		for i := range a {
+			if c := compareKeys(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+
+	return diamonds
+}
+
+// CutEdges returns the edges that cross the boundary of a set of nodes: in
+// are the edges from nodes outside the set to nodes in the set, and out are
+// the edges from nodes in the set to nodes outside it. Both are in a
+// deterministic order. Keys in set that are not nodes of the graph are
+// ignored.
+func (g *Graph[Key]) CutEdges(set []Key) (in, out [][2]Key) {
+	inside := make(map[Key]bool, len(set))
+	for _, k := range set {
+		inside[g.key(k)] = true
+	}
+
+	for from, e := range g.nodes {
+		for to := range e {
+			switch {
+			case inside[from] && !inside[to]:
+				out = append(out, [2]Key{from, to})
+			case !inside[from] && inside[to]:
+				in = append(in, [2]Key{from, to})
+			}
+		}
+	}
+
+	sortEdges(in)
+	sortEdges(out)
+
+	return in, out
+}
+
+// SplitAt removes the cut nodes from the graph and returns the connected
+// components of the nodes that remain as separate graphs, when the direction
+// of the edges is ignored. Every graph has the edges between its nodes. The
+// cut nodes are not part of any of the graphs; CutEdges can be used to find
+// the edges between them and a graph. The graphs are ordered by their
+// smallest node, and use the same key normalizer. Cut nodes that do not exist
+// are ignored. The graph itself is not modified.
+func (g *Graph[Key]) SplitAt(cuts []Key) []*Graph[Key] {
+	exclude := make(map[Key]bool, len(cuts))
+	for _, k := range cuts {
+		exclude[g.key(k)] = true
+	}
+
+	var graphs []*Graph[Key]
+	for _, c := range g.connected(exclude) {
+		keep := make(map[Key]bool, len(c))
+		for _, k := range c {
+			keep[k] = true
+		}
+		graphs = append(graphs, g.subgraph(keep))
+	}
+
+	return graphs
+}
+
+// IsPolytree reports whether the graph is a polytree, or a forest of them: an
+// acyclic graph that is also acyclic when the direction of the edges is
+// ignored. In a polytree there is at most one path between any two nodes,
+// regardless of direction, so there are no diamonds. An empty graph is a
+// polytree.
+func (g *Graph[Key]) IsPolytree() bool {
+	if !g.kahn(func(Key) bool { return true }) {
+		return false
+	}
+
+	// An acyclic graph has no edges in both directions between two nodes, so
+	// every edge is a separate undirected edge. A forest has exactly one edge
+	// less than nodes in every connected component.
+	edges := 0
+	for _, e := range g.nodes {
+		edges += len(e)
+	}
+
+	return edges == len(g.nodes)-len(g.connected(nil))
+}
+
+// Violations returns the edges for which allowed returns false, in a
+// deterministic order. With a function that encodes which nodes may depend on
+// which, such as architectural layering rules, these are the edges that break
+// the rules. The function is called once for every edge.
+func (g *Graph[Key]) Violations(allowed func(from, to Key) bool) [][2]Key {
+	var violations [][2]Key
+	for from, e := range g.nodes {
+		for to := range e {
+			if !allowed(from, to) {
+				violations = append(violations, [2]Key{from, to})
+			}
+		}
+	}
+
+	sortEdges(violations)
+
+	return violations
+}
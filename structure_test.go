@@ -0,0 +1,170 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiamonds(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: r -> a, a -> b,
+	// a -> c, b -> d, c -> d, b -> e, r -> x, x -> z, r -> y and y -> z. The
+	// diamonds are a, b, c, d and r, x, y, z; r is a common ancestor of b
+	// and c too, but not the nearest one.
+	g.Edge("r", "a")
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "d")
+	g.Edge("c", "d")
+	g.Edge("b", "e")
+	g.Edge("r", "y")
+	g.Edge("r", "x")
+	g.Edge("y", "z")
+	g.Edge("x", "z")
+
+	expected := [][4]string{{"a", "b", "c", "d"}, {"r", "x", "y", "z"}}
+	for i := 0; i < 10; i++ {
+		if diamonds := g.Diamonds(); !reflect.DeepEqual(diamonds, expected) {
+			t.Errorf("expected %v, got %v", expected, diamonds)
+			return
+		}
+	}
+}
+
+func TestDiamondsNone(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c, a -> c.
+	// The predecessors of c are a and b, but a reaches b, so this is not a
+	// diamond.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("a", "c")
+
+	if diamonds := g.Diamonds(); len(diamonds) != 0 {
+		t.Errorf("expected no diamonds, got %v", diamonds)
+	}
+}
+
+func TestCutEdges(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> d,
+	// e -> c and b -> c. For the set {b c}, a -> b and e -> c come in, and
+	// c -> d goes out.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("e", "c")
+
+	in, out := g.CutEdges([]string{"b", "c"})
+
+	if !reflect.DeepEqual(in, [][2]string{{"a", "b"}, {"e", "c"}}) {
+		t.Errorf("expected [[a b] [e c]], got %v", in)
+	}
+
+	if !reflect.DeepEqual(out, [][2]string{{"c", "d"}}) {
+		t.Errorf("expected [[c d]], got %v", out)
+	}
+}
+
+func TestSplitAt(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> x -> c,
+	// d -> x, c -> e and y -> f. Cutting at x and y leaves {a b}, {c e}, {d}
+	// and {f}.
+	g.Edge("a", "b")
+	g.Edge("b", "x")
+	g.Edge("x", "c")
+	g.Edge("d", "x")
+	g.Edge("c", "e")
+	g.Edge("y", "f")
+
+	graphs := g.SplitAt([]string{"x", "y", "z"})
+
+	expected := []map[string]Edges[string]{
+		{"a": {"b": true}, "b": {}},
+		{"c": {"e": true}, "e": {}},
+		{"d": {}},
+		{"f": {}},
+	}
+
+	if len(graphs) != len(expected) {
+		t.Errorf("expected %v graphs, got %v", len(expected), len(graphs))
+		return
+	}
+
+	for i, s := range graphs {
+		if !reflect.DeepEqual(s.nodes, expected[i]) {
+			t.Errorf("expected %v, got %v", expected[i], s.nodes)
+		}
+	}
+
+	// The graph must not be modified.
+	if !g.HasEdge("b", "x") {
+		t.Error("expected b -> x to be kept")
+	}
+}
+
+func TestIsPolytree(t *testing.T) {
+	g := New[string]()
+
+	if !g.IsPolytree() {
+		t.Error("expected an empty graph to be a polytree")
+	}
+
+	// We construct a graph with the following structure: a -> b, c -> b,
+	// b -> d and a separate tree e -> f. The edges have different directions,
+	// but there is only one path between any two nodes.
+	g.Edge("a", "b")
+	g.Edge("c", "b")
+	g.Edge("b", "d")
+	g.Edge("e", "f")
+
+	if !g.IsPolytree() {
+		t.Error("expected a polytree")
+	}
+
+	// The edge a -> d adds a second path from a to d.
+	g.Edge("a", "d")
+
+	if g.IsPolytree() {
+		t.Error("expected no polytree with two paths from a to d")
+	}
+
+	// A cycle is not a polytree either.
+	h := New[string]()
+	h.Edge("a", "b")
+	h.Edge("b", "a")
+
+	if h.IsPolytree() {
+		t.Error("expected no polytree with a cycle")
+	}
+}
+
+func TestViolations(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph of layers with the following structure:
+	// ui -> service -> db, ui -> db and db -> ui. Only edges to the next
+	// layer are allowed.
+	g.Edge("ui", "service")
+	g.Edge("service", "db")
+	g.Edge("ui", "db")
+	g.Edge("db", "ui")
+
+	layer := map[string]int{"ui": 0, "service": 1, "db": 2}
+
+	violations := g.Violations(func(from, to string) bool {
+		return layer[to] == layer[from]+1
+	})
+	expected := [][2]string{{"db", "ui"}, {"ui", "db"}}
+
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("expected %v, got %v", expected, violations)
+	}
+}
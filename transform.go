@@ -0,0 +1,230 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import "math/rand/v2"
+
+// CollapseChains contracts every maximal chain of nodes with exactly one
+// incoming and one outgoing edge into a single node. It returns the reduced
+// graph, with integer keys, and the members of every node of the reduced
+// graph, indexed by its key. The members of a chain are in the order of the
+// chain; every other node becomes a node of its own. A chain that forms a
+// cycle on its own becomes a single node with an edge to itself.
+//
+// The reduced graph has an edge between two nodes if the original graph has
+// an edge between their members, so reachability between the nodes that are
+// not part of a chain is preserved. Keys are assigned in a deterministic
+// order.
+func (g *Graph[Key]) CollapseChains() (*Graph[int], [][]Key) {
+	r := g.Reverse()
+
+	// A node is part of a chain if it has exactly one incoming and one
+	// outgoing edge, other than an edge to itself.
+	chain := func(k Key) bool {
+		return len(g.nodes[k]) == 1 && len(r.nodes[k]) == 1 && !g.nodes[k][k]
+	}
+
+	// only returns the only key in a set of edges.
+	only := func(e Edges[Key]) Key {
+		for k := range e {
+			return k
+		}
+		panic("no edges")
+	}
+
+	group := make(map[Key]int, len(g.nodes))
+	var members [][]Key
+
+	// collect adds the chain that starts at a node as a new group.
+	collect := func(start Key) {
+		id := len(members)
+		var m []Key
+		for n := start; ; n = only(g.nodes[n]) {
+			if _, ok := group[n]; ok || !chain(n) {
+				break
+			}
+			group[n] = id
+			m = append(m, n)
+		}
+		members = append(members, m)
+	}
+
+	keys := g.keys()
+
+	// We start every chain at its first node, which is a node whose
+	// predecessor is not part of a chain. Other nodes become groups of their
+	// own.
+	for _, k := range keys {
+		if !chain(k) {
+			group[k] = len(members)
+			members = append(members, []Key{k})
+		} else if !chain(only(r.nodes[k])) {
+			collect(k)
+		}
+	}
+
+	// The remaining chain nodes are part of chains that form a cycle, which
+	// we start at an arbitrary node.
+	for _, k := range keys {
+		if _, ok := group[k]; !ok {
+			collect(k)
+		}
+	}
+
+	reduced := New[int]()
+	for id := range members {
+		reduced.Node(id)
+	}
+
+	for from, e := range g.nodes {
+		for to := range e {
+			f, t := group[from], group[to]
+
+			// Edges within a chain are contracted, except for the edge that
+			// closes a chain that forms a cycle.
+			if f == t && from != to && members[f][0] != to {
+				continue
+			}
+
+			reduced.Edge(f, t)
+		}
+	}
+
+	return reduced, members
+}
+
+// LineGraph returns the line graph of the graph, in which every node is an
+// edge of the graph, and there is an edge from a -> b to b -> c for every two
+// edges that form a path. Nodes without edges are not part of the line graph.
+// If the graph has a key normalizer, the line graph normalizes both keys of
+// its nodes with it.
+//
+// LineGraph is a function rather than a method, because a method of Graph[Key]
+// cannot return a Graph[[2]Key]: Go does not allow a generic type to refer to
+// itself with a type argument that grows with every instantiation.
+func LineGraph[Key comparable](g *Graph[Key]) *Graph[[2]Key] {
+	l := New[[2]Key]()
+	if normalize := g.normalize; normalize != nil {
+		l.normalize = func(e [2]Key) [2]Key {
+			return [2]Key{normalize(e[0]), normalize(e[1])}
+		}
+	}
+
+	for from, e := range g.nodes {
+		for to := range e {
+			n := l.node([2]Key{from, to})
+			for next := range g.nodes[to] {
+				l.node([2]Key{to, next})
+				n.add([2]Key{to, next})
+			}
+		}
+	}
+
+	return l
+}
+
+// GroupBy returns a new graph in which every node of the graph is replaced by
+// its group, for example to turn a graph of files into a graph of packages.
+// Nodes in the same group become a single node, with an edge to another group
+// if any of them has an edge to a node of that group. Edges within a group,
+// including self-loops, are dropped, so the new graph has no self-loops. The
+// new graph uses the same key normalizer, which is also applied to the
+// groups.
+func (g *Graph[Key]) GroupBy(group func(Key) Key) *Graph[Key] {
+	grouped := New[Key]()
+	grouped.normalize = g.normalize
+
+	groups := make(map[Key]Key, len(g.nodes))
+	for k := range g.nodes {
+		gk := grouped.key(group(k))
+		groups[k] = gk
+		grouped.node(gk)
+	}
+
+	for from, e := range g.nodes {
+		for to := range e {
+			if f, t := groups[from], groups[to]; f != t {
+				grouped.link(f, t)
+			}
+		}
+	}
+
+	return grouped
+}
+
+// Quotient returns a new graph in which every node is merged into its
+// representative, for example to merge duplicate versions of a module into
+// one, together with the representative of every node. Unlike GroupBy, rep
+// must return a node of the graph, so the nodes of the new graph are nodes of
+// the graph. A node for which rep returns a key that is not a node is its own
+// representative. Like GroupBy, edges within a group are dropped, and the new
+// graph uses the same key normalizer. The representative of a representative
+// should be itself; otherwise, its nodes end up in a group without it.
+func (g *Graph[Key]) Quotient(rep func(Key) Key) (*Graph[Key], map[Key]Key) {
+	reps := make(map[Key]Key, len(g.nodes))
+	for k := range g.nodes {
+		r := g.key(rep(k))
+		if _, ok := g.nodes[r]; !ok {
+			r = k
+		}
+		reps[k] = r
+	}
+
+	return g.GroupBy(func(k Key) Key { return reps[k] }), reps
+}
+
+// Sample returns a smaller graph with a random fraction keep of the nodes, in
+// which b can be reached from a if and only if it can in the graph, to show
+// the overall structure of a large graph. For every node a that is kept, and
+// every kept node b that a reaches through nodes that are not kept, directly
+// or not, the sample has an edge a -> b. The nodes that are kept only depend
+// on seed, so the same seed gives the same sample for equal graphs. The new
+// graph uses the same key normalizer.
+//
+// The shortcut edges are found with a search from every kept node through
+// the nodes that are not kept, which takes O(k * (n + m)) time in the worst
+// case for k = [number of kept nodes], n = [number of nodes] and
+// m = [number of edges]. The sample can have more edges than the graph, up to
+// k^2 when the dropped nodes connect many kept nodes.
+func (g *Graph[Key]) Sample(keep float64, seed int64) *Graph[Key] {
+	r := rand.New(rand.NewPCG(uint64(seed), 0))
+
+	kept := make(map[Key]bool)
+	for _, k := range g.keys() {
+		if r.Float64() < keep {
+			kept[k] = true
+		}
+	}
+
+	s := New[Key]()
+	s.normalize = g.normalize
+
+	for a := range kept {
+		s.node(a)
+
+		// We search from a, and stop at every kept node, as the nodes
+		// behind it are reached through its own edges.
+		visited := make(map[Key]bool)
+		stack := []Key{a}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			for m := range g.nodes[n] {
+				if visited[m] {
+					continue
+				}
+				visited[m] = true
+
+				if kept[m] {
+					s.node(m)
+					s.link(a, m)
+				} else {
+					stack = append(stack, m)
+				}
+			}
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,175 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCollapseChains(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> d,
+	// a -> e -> d, d -> f, and a separate cycle x -> y -> x. The chains are
+	// [b c], [e] and [x y]; a, d and f are nodes of their own.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("a", "e")
+	g.Edge("e", "d")
+	g.Edge("d", "f")
+	g.Edge("x", "y")
+	g.Edge("y", "x")
+
+	reduced, members := g.CollapseChains()
+
+	id := make(map[string]int)
+	for i, m := range members {
+		id[m[0]] = i
+	}
+
+	expected := [][]string{{"a"}, {"b", "c"}, {"d"}, {"e"}, {"f"}, {"x", "y"}}
+	if !reflect.DeepEqual(members, expected) {
+		t.Errorf("expected %v, got %v", expected, members)
+		return
+	}
+
+	expectedEdges := map[int]Edges[int]{
+		id["a"]: {id["b"]: true, id["e"]: true},
+		id["b"]: {id["d"]: true},
+		id["d"]: {id["f"]: true},
+		id["e"]: {id["d"]: true},
+		id["f"]: {},
+		id["x"]: {id["x"]: true},
+	}
+
+	if !reflect.DeepEqual(reduced.nodes, expectedEdges) {
+		t.Errorf("expected %v, got %v", expectedEdges, reduced.nodes)
+	}
+}
+
+func TestLineGraph(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c,
+	// b -> d, d -> d, and e without edges. The edge a -> b is followed by both
+	// edges of b, and the self-loop on d follows itself.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("b", "d")
+	g.Edge("d", "d")
+	g.Node("e")
+
+	l := LineGraph(g)
+
+	expected := map[[2]string]Edges[[2]string]{
+		{"a", "b"}: {{"b", "c"}: true, {"b", "d"}: true},
+		{"b", "c"}: {},
+		{"b", "d"}: {{"d", "d"}: true},
+		{"d", "d"}: {{"d", "d"}: true},
+	}
+
+	if !reflect.DeepEqual(l.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, l.nodes)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph of files with the following structure:
+	// a/x -> a/y, a/x -> b/x, a/y -> b/y, b/x -> c/x and c/x -> c/x. Grouped by
+	// directory, a -> b -> c remains, while the edges within a and c are
+	// dropped.
+	g.Edge("a/x", "a/y")
+	g.Edge("a/x", "b/x")
+	g.Edge("a/y", "b/y")
+	g.Edge("b/x", "c/x")
+	g.Edge("c/x", "c/x")
+
+	grouped := g.GroupBy(func(k string) string {
+		dir, _, _ := strings.Cut(k, "/")
+		return dir
+	})
+
+	expected := map[string]Edges[string]{
+		"a": {"b": true},
+		"b": {"c": true},
+		"c": {},
+	}
+
+	if !reflect.DeepEqual(grouped.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, grouped.nodes)
+	}
+}
+
+func TestQuotient(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph of versioned modules with the following
+	// structure: app -> lib@1, tool -> lib@2, lib@2 -> util@1,
+	// util@1 -> lib@1, lib@1 -> lib@2 and the unversioned node lib. Merging
+	// every version into the node without a version drops lib@1 -> lib@2,
+	// but util is not a node, so util@1 is kept.
+	g.Edge("app", "lib@1")
+	g.Edge("tool", "lib@2")
+	g.Edge("lib@2", "util@1")
+	g.Edge("util@1", "lib@1")
+	g.Edge("lib@1", "lib@2")
+	g.Node("lib")
+
+	q, reps := g.Quotient(func(k string) string {
+		name, _, _ := strings.Cut(k, "@")
+		return name
+	})
+
+	expected := map[string]Edges[string]{
+		"app":    {"lib": true},
+		"tool":   {"lib": true},
+		"lib":    {"util@1": true},
+		"util@1": {"lib": true},
+	}
+
+	if !reflect.DeepEqual(q.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, q.nodes)
+	}
+
+	if reps["lib@2"] != "lib" || reps["util@1"] != "util@1" || reps["lib"] != "lib" {
+		t.Errorf("expected lib, util@1 and lib, got %v", reps)
+	}
+}
+
+func TestSample(t *testing.T) {
+	g := buildGraph(200)
+
+	s := g.Sample(0.3, 1)
+
+	if !reflect.DeepEqual(s.nodes, g.Sample(0.3, 1).nodes) {
+		t.Errorf("expected the same sample for the same seed")
+	}
+
+	if len(s.nodes) == 0 || len(s.nodes) >= len(g.nodes) {
+		t.Errorf("expected a part of the nodes, got %v", len(s.nodes))
+	}
+
+	// Reachability between the kept nodes is the same as in the graph.
+	for a := range s.nodes {
+		expected := reachable(g.nodes, a)
+		got := reachable(s.nodes, a)
+		for b := range s.nodes {
+			if expected[b] != got[b] {
+				t.Errorf("expected reachability of %v from %v to be %v", b, a, expected[b])
+			}
+		}
+	}
+
+	if full := g.Sample(1, 1); !reflect.DeepEqual(full.nodes, g.nodes) {
+		t.Errorf("expected the whole graph")
+	}
+
+	if empty := g.Sample(0, 1); len(empty.nodes) != 0 {
+		t.Errorf("expected an empty graph, got %v", empty.nodes)
+	}
+}
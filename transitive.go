@@ -0,0 +1,139 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+// RedundantEdges returns the edges a -> b for which b can also be reached
+// from a through a longer path, in a deterministic order. In an acyclic graph
+// these are exactly the edges a transitive reduction removes: removing all of
+// them does not change which nodes can be reached from which. It does not
+// modify the graph.
+//
+// On a cyclic graph the longer path may go through the edge itself, for
+// example when a -> b is part of a cycle that also contains a -> c -> a, so
+// removing all reported edges at once can change reachability there.
+// Self-loops are never reported.
+//
+// RedundantEdges does a search from the successors of every node, so it takes
+// O(n * (n + m)) time, for n = [number of nodes] and m = [number of edges].
+func (g *Graph[Key]) RedundantEdges() [][2]Key {
+	return g.splitEdges(true)
+}
+
+// ReductionEdges returns the edges of the transitive reduction of the graph,
+// in a deterministic order: the edges a -> b for which there is no longer path
+// from a to b. The transitive reduction is the smallest graph with the same
+// reachability, so these are the edges to draw when only the dependencies that
+// matter should be shown. It returns ErrCycle if the graph has a cycle, as the
+// transitive reduction of a cyclic graph is not unique.
+//
+// Like RedundantEdges, it takes O(n * (n + m)) time, for n = [number of
+// nodes] and m = [number of edges].
+func (g *Graph[Key]) ReductionEdges() ([][2]Key, error) {
+	if !g.kahn(func(Key) bool { return true }) {
+		return nil, ErrCycle
+	}
+
+	return g.splitEdges(false), nil
+}
+
+// splitEdges returns the redundant edges of the graph, as RedundantEdges
+// defines them, if redundant is true, and all other edges otherwise, in a
+// deterministic order. For an acyclic graph, the other edges are those of the
+// transitive reduction.
+func (g *Graph[Key]) splitEdges(redundant bool) [][2]Key {
+	var edges [][2]Key
+
+	for from, e := range g.nodes {
+		// The nodes that can be reached from the successors of from, through
+		// at least one more edge. Edges to those nodes are redundant. With a
+		// single edge, a longer path would start with the edge itself, so we
+		// do not search.
+		var indirect map[Key]bool
+		if len(e) >= 2 {
+			var successors []Key
+			for to := range e {
+				successors = append(successors, to)
+			}
+			indirect = reachable(g.nodes, successors...)
+		}
+
+		for to := range e {
+			if (to != from && indirect[to]) == redundant {
+				edges = append(edges, [2]Key{from, to})
+			}
+		}
+	}
+
+	sortEdges(edges)
+
+	return edges
+}
+
+// MinimalEquivalent returns the graph with the fewest edges that has exactly
+// the same topological orders as the graph. For an acyclic graph this is its
+// transitive reduction, with all nodes of the graph and the edges returned by
+// ReductionEdges. Two acyclic graphs allow the same orders if and only if
+// their minimal equivalents are equal. It returns ErrCycle if the graph has a
+// cycle, as a cyclic graph has no topological orders. The new graph uses the
+// same key normalizer.
+func (g *Graph[Key]) MinimalEquivalent() (*Graph[Key], error) {
+	edges, err := g.ReductionEdges()
+	if err != nil {
+		return nil, err
+	}
+
+	m := New[Key]()
+	m.normalize = g.normalize
+
+	for k := range g.nodes {
+		m.node(k)
+	}
+	for _, e := range edges {
+		m.link(e[0], e[1])
+	}
+
+	return m, nil
+}
+
+// ReduceAround returns a copy of the graph in which only the redundant edges
+// that start or end at one of the given nodes are removed, to declutter one
+// region of a diagram without changing the rest. An edge a -> b is redundant
+// if b can also be reached from a through a longer path, like in
+// RedundantEdges, and it is removed if a or b is one of the given nodes. All
+// other edges are kept, even if they are redundant. As the graph is acyclic,
+// the longer path of a removed edge never depends on another removed edge, so
+// reachability does not change. Keys that do not exist are ignored. It
+// returns ErrCycle if the graph has a cycle. The new graph uses the same key
+// normalizer.
+func (g *Graph[Key]) ReduceAround(keys []Key) (*Graph[Key], error) {
+	if !g.kahn(func(Key) bool { return true }) {
+		return nil, ErrCycle
+	}
+
+	around := make(map[Key]bool, len(keys))
+	for _, k := range keys {
+		around[g.key(k)] = true
+	}
+
+	reduced := g.Copy()
+
+	for from, e := range g.nodes {
+		if len(e) < 2 {
+			continue
+		}
+
+		var successors []Key
+		for to := range e {
+			successors = append(successors, to)
+		}
+		indirect := reachable(g.nodes, successors...)
+
+		for to := range e {
+			if indirect[to] && (around[from] || around[to]) {
+				reduced.unlink(from, to)
+			}
+		}
+	}
+
+	return reduced, nil
+}
@@ -0,0 +1,157 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestRedundantEdges(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> d,
+	// a -> c, a -> d and b -> e. The edges a -> c and a -> d are redundant.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("a", "c")
+	g.Edge("a", "d")
+	g.Edge("b", "e")
+
+	redundant := g.RedundantEdges()
+	expected := [][2]string{{"a", "c"}, {"a", "d"}}
+
+	if !reflect.DeepEqual(redundant, expected) {
+		t.Errorf("expected %v, got %v", expected, redundant)
+	}
+
+	// The graph must not be modified.
+	if !g.HasEdge("a", "c") || !g.HasEdge("a", "d") {
+		t.Error("expected edges to be kept")
+	}
+}
+
+func TestReductionEdges(t *testing.T) {
+	g := New[string]()
+
+	// We use the same graph as TestRedundantEdges: a -> b -> c -> d, a -> c,
+	// a -> d and b -> e. Only the edges on the longest paths remain.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("a", "c")
+	g.Edge("a", "d")
+	g.Edge("b", "e")
+
+	edges, err := g.ReductionEdges()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := [][2]string{{"a", "b"}, {"b", "c"}, {"b", "e"}, {"c", "d"}}
+
+	if !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected %v, got %v", expected, edges)
+	}
+
+	g.Edge("d", "a")
+
+	if _, err := g.ReductionEdges(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestMinimalEquivalent(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c,
+	// a -> c, a -> d and d -> c. The edge a -> c is implied by the other
+	// edges, so removing it does not allow any other orders.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("a", "c")
+	g.Edge("a", "d")
+	g.Edge("d", "c")
+
+	m, err := g.MinimalEquivalent()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := map[string]Edges[string]{
+		"a": {"b": true, "d": true},
+		"b": {"c": true},
+		"c": {},
+		"d": {"c": true},
+	}
+
+	if !reflect.DeepEqual(m.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, m.nodes)
+	}
+
+	// Both graphs must have the same topological orders.
+	orders := slices.Collect(g.TopologicalOrders())
+	minimal := slices.Collect(m.TopologicalOrders())
+
+	if len(orders) != 2 || !reflect.DeepEqual(orders, minimal) {
+		t.Errorf("expected the same 2 orders, got %v and %v", orders, minimal)
+	}
+
+	g.Edge("c", "a")
+
+	if _, err := g.MinimalEquivalent(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestReduceAround(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c,
+	// a -> c, x -> y -> z and x -> z. Both a -> c and x -> z are redundant,
+	// but only the edges around c are reduced.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("a", "c")
+	g.Edge("x", "y")
+	g.Edge("y", "z")
+	g.Edge("x", "z")
+
+	r, err := g.ReduceAround([]string{"c"})
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := map[string]Edges[string]{
+		"a": {"b": true},
+		"b": {"c": true},
+		"c": {},
+		"x": {"y": true, "z": true},
+		"y": {"z": true},
+		"z": {},
+	}
+
+	if !reflect.DeepEqual(r.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, r.nodes)
+	}
+
+	// The graph itself is not modified.
+	if !g.HasEdge("a", "c") {
+		t.Errorf("expected a -> c to remain in the graph")
+	}
+
+	g.Edge("c", "a")
+
+	if _, err := g.ReduceAround([]string{"c"}); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
@@ -0,0 +1,398 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import "slices"
+
+// DFS performs a depth-first traversal of the nodes that can be reached from
+// start, calling pre when it enters a node and post when it leaves it, after
+// all nodes reachable from it have been visited. Either function can be nil.
+// Every node is visited at most once, so cycles are handled. It does nothing
+// if start does not exist.
+//
+// For an acyclic graph, the order in which post is called is a reverse
+// topological order of the reachable nodes.
+func (g *Graph[Key]) DFS(start Key, pre, post func(Key)) {
+	start = g.key(start)
+	if _, ok := g.nodes[start]; !ok {
+		return
+	}
+
+	visited := make(map[Key]bool)
+
+	var visit func(n Key)
+	visit = func(n Key) {
+		visited[n] = true
+		if pre != nil {
+			pre(n)
+		}
+
+		for m := range g.nodes[n] {
+			if !visited[m] {
+				visit(m)
+			}
+		}
+
+		if post != nil {
+			post(n)
+		}
+	}
+
+	visit(start)
+}
+
+// BFSLevels returns the distance, in edges, from start to every node that can
+// be reached from it, using a breadth-first search. The distance of start
+// itself is 0. Nodes that cannot be reached are not part of the result. It
+// returns nil if start does not exist.
+func (g *Graph[Key]) BFSLevels(start Key) map[Key]int {
+	start = g.key(start)
+	if _, ok := g.nodes[start]; !ok {
+		return nil
+	}
+
+	levels := map[Key]int{start: 0}
+	queue := []Key{start}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		for m := range g.nodes[n] {
+			if _, ok := levels[m]; !ok {
+				levels[m] = levels[n] + 1
+				queue = append(queue, m)
+			}
+		}
+	}
+
+	return levels
+}
+
+// ReachableWithin reports whether to can be reached from from by following at
+// most k edges. Like BFSLevels, the distance from a node to itself is 0, so it
+// returns true for from == to if the node exists and k is not negative. The
+// breadth-first search stops as soon as it reaches to, and never follows
+// edges beyond a distance of k. It returns false if one of the nodes does not
+// exist.
+func (g *Graph[Key]) ReachableWithin(from, to Key, k int) bool {
+	from, to = g.key(from), g.key(to)
+	if _, ok := g.nodes[from]; !ok || k < 0 {
+		return false
+	}
+	if from == to {
+		return true
+	}
+
+	levels := map[Key]int{from: 0}
+	queue := []Key{from}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		if levels[n] == k {
+			continue
+		}
+
+		for m := range g.nodes[n] {
+			if m == to {
+				return true
+			}
+			if _, ok := levels[m]; !ok {
+				levels[m] = levels[n] + 1
+				queue = append(queue, m)
+			}
+		}
+	}
+
+	return false
+}
+
+// AtDistance returns the nodes whose distance from from is exactly d edges, in
+// a deterministic order, where the distance is the length of the shortest path
+// like in BFSLevels. These are the nodes of a single level, such as only the
+// dependencies of the dependencies of a node for d = 2, without the nodes at a
+// smaller distance. For d = 0, it is only from itself. The breadth-first
+// search never follows edges beyond a distance of d. It returns an empty
+// slice if no node is at that distance, or if from does not exist.
+func (g *Graph[Key]) AtDistance(from Key, d int) []Key {
+	from = g.key(from)
+	if _, ok := g.nodes[from]; !ok || d < 0 {
+		return []Key{}
+	}
+
+	levels := map[Key]int{from: 0}
+	queue := []Key{from}
+
+	shell := []Key{}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		if levels[n] == d {
+			shell = append(shell, n)
+			continue
+		}
+
+		for m := range g.nodes[n] {
+			if _, ok := levels[m]; !ok {
+				levels[m] = levels[n] + 1
+				queue = append(queue, m)
+			}
+		}
+	}
+	sortKeys(shell)
+
+	return shell
+}
+
+// Neighborhood returns the subgraph of the nodes that are at most radius edges
+// away from center, ignoring the direction of the edges, with all edges
+// between those nodes. It returns an empty graph if center does not exist.
+//
+// The graph only stores the outgoing edges of every node, so to find the
+// incoming edges of the nodes found at every distance, Neighborhood scans all
+// edges of the graph once. It does not copy the graph, so it only needs memory
+// for the neighborhood, but it takes O(r * n) time for r = radius and
+// n = [number of nodes] + [number of edges].
+func (g *Graph[Key]) Neighborhood(center Key, radius int) *Graph[Key] {
+	center = g.key(center)
+
+	keep := make(map[Key]bool)
+	if _, ok := g.nodes[center]; ok {
+		keep[center] = true
+
+		// A breadth-first search over the edges in both directions, that
+		// stops at the given radius.
+		level := map[Key]bool{center: true}
+		for d := 0; d < radius && len(level) > 0; d++ {
+			next := make(map[Key]bool)
+			for from, e := range g.nodes {
+				if level[from] {
+					for to := range e {
+						if !keep[to] {
+							next[to] = true
+						}
+					}
+				} else if !keep[from] {
+					for to := range e {
+						if level[to] {
+							next[from] = true
+							break
+						}
+					}
+				}
+			}
+			for k := range next {
+				keep[k] = true
+			}
+			level = next
+		}
+	}
+
+	return g.subgraph(keep)
+}
+
+// WalkDepth walks the tree of paths from start, like an expandable tree of
+// dependencies, calling visit for every node with its depth along the path,
+// where start has depth 0. Nodes below maxDepth are not visited, and when
+// visit returns false the nodes below the visited node are not visited
+// either. A node that can be reached through several paths is visited once
+// for every path, but a path never visits a node twice, so cycles are cut
+// off. The edges of every node are followed in a deterministic order. It does
+// nothing if start does not exist.
+func (g *Graph[Key]) WalkDepth(start Key, maxDepth int, visit func(key Key, depth int) bool) {
+	start = g.key(start)
+	if _, ok := g.nodes[start]; !ok || maxDepth < 0 {
+		return
+	}
+
+	// The nodes on the current path.
+	path := make(map[Key]bool)
+
+	var walk func(n Key, depth int)
+	walk = func(n Key, depth int) {
+		if !visit(n, depth) || depth == maxDepth {
+			return
+		}
+
+		path[n] = true
+
+		next := make([]Key, 0, len(g.nodes[n]))
+		for m := range g.nodes[n] {
+			if !path[m] {
+				next = append(next, m)
+			}
+		}
+		sortKeys(next)
+
+		for _, m := range next {
+			walk(m, depth+1)
+		}
+
+		path[n] = false
+	}
+
+	walk(start, 0)
+}
+
+// MaximalChains returns every path that starts at a node without incoming
+// edges and ends at a node without outgoing edges, such as every end-to-end
+// sequence of steps of a pipeline. A node without any edges is a path of its
+// own. The paths are in a deterministic order: by their first node, and then
+// by the order of the edges they follow. It returns ErrCycle if the graph has a
+// cycle.
+//
+// The number of paths can be exponential in the number of nodes, for example
+// in a chain of diamonds, where every diamond doubles the number of paths, so
+// MaximalChains is only suitable for graphs with few alternative paths.
+func (g *Graph[Key]) MaximalChains() ([][]Key, error) {
+	if !g.kahn(func(Key) bool { return true }) {
+		return nil, ErrCycle
+	}
+
+	indegree := g.InDegrees()
+
+	var chains [][]Key
+	var path []Key
+
+	var walk func(n Key)
+	walk = func(n Key) {
+		path = append(path, n)
+		defer func() { path = path[:len(path)-1] }()
+
+		if len(g.nodes[n]) == 0 {
+			chains = append(chains, slices.Clone(path))
+			return
+		}
+
+		next := make([]Key, 0, len(g.nodes[n]))
+		for m := range g.nodes[n] {
+			next = append(next, m)
+		}
+		sortKeys(next)
+
+		for _, m := range next {
+			walk(m)
+		}
+	}
+
+	for _, k := range g.keys() {
+		if indegree[k] == 0 {
+			walk(k)
+		}
+	}
+
+	return chains, nil
+}
+
+// PathsFromRoots returns every path from a node without incoming edges to
+// target, which explain why target is included when the roots are the entry
+// points. A path never visits a node twice, so cycles are cut off, and paths
+// only through a cycle that no root reaches are not found. If target has no
+// incoming edges, the only path is target itself. The paths are ordered by
+// their nodes, deterministically. It returns nil if target does not exist.
+//
+// The number of paths can be exponential in the number of nodes, so
+// PathsFromRoots is meant to explain a single target in a graph with few
+// alternative paths.
+func (g *Graph[Key]) PathsFromRoots(target Key) [][]Key {
+	target = g.key(target)
+	if _, ok := g.nodes[target]; !ok {
+		return nil
+	}
+
+	// We search backwards from target, over the reversed graph, until we
+	// reach a node without incoming edges.
+	r := g.Reverse()
+
+	var paths [][]Key
+	var path []Key
+	on := make(map[Key]bool)
+
+	var walk func(n Key)
+	walk = func(n Key) {
+		path = append(path, n)
+		on[n] = true
+		defer func() {
+			path = path[:len(path)-1]
+			on[n] = false
+		}()
+
+		if len(r.nodes[n]) == 0 {
+			p := slices.Clone(path)
+			slices.Reverse(p)
+			paths = append(paths, p)
+			return
+		}
+
+		for m := range r.nodes[n] {
+			if !on[m] {
+				walk(m)
+			}
+		}
+	}
+
+	walk(target)
+
+	slices.SortFunc(paths, func(a, b []Key) int {
+		for i := 0; i < len(a) && i < len(b); i++ {
+			if c := compareKeys(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+		return len(a) - len(b)
+	})
+
+	return paths
+}
+
+// SpanningTree returns a tree of edges of the graph that connects root to
+// every node that can be reached from it, where every node other than root has
+// exactly one incoming edge, from its parent, together with the nodes that
+// cannot be reached from root, in a deterministic order. The tree is built
+// with a breadth-first search, so the path to every node in the tree is a
+// shortest path in the graph. The search follows the edges of every node in a
+// deterministic order, so the tree is the same for equal graphs. The tree uses
+// the same key normalizer. It returns nil for both if root does not exist.
+func (g *Graph[Key]) SpanningTree(root Key) (*Graph[Key], []Key) {
+	root = g.key(root)
+	if _, ok := g.nodes[root]; !ok {
+		return nil, nil
+	}
+
+	tree := New[Key]()
+	tree.normalize = g.normalize
+	tree.node(root)
+
+	queue := []Key{root}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		next := make([]Key, 0, len(g.nodes[n]))
+		for m := range g.nodes[n] {
+			if _, ok := tree.nodes[m]; !ok {
+				next = append(next, m)
+			}
+		}
+		sortKeys(next)
+
+		for _, m := range next {
+			tree.node(m)
+			tree.link(n, m)
+			queue = append(queue, m)
+		}
+	}
+
+	var unreached []Key
+	for k := range g.nodes {
+		if _, ok := tree.nodes[k]; !ok {
+			unreached = append(unreached, k)
+		}
+	}
+	sortKeys(unreached)
+
+	return tree, unreached
+}
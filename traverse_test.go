@@ -0,0 +1,325 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestDFS(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> d,
+	// a -> c -> d, and an unreachable node e -> a.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "d")
+	g.Edge("c", "d")
+	g.Edge("e", "a")
+
+	var pre, post []string
+	g.DFS("a", func(k string) {
+		pre = append(pre, k)
+	}, func(k string) {
+		post = append(post, k)
+	})
+
+	if len(pre) != 4 || pre[0] != "a" {
+		t.Errorf("expected 4 nodes starting with a, got %v", pre)
+	}
+
+	// The reverse of the post-order is a topological order of the reachable
+	// nodes.
+	slices.Reverse(post)
+	checkOrder(t, g.subgraph(map[string]bool{"a": true, "b": true, "c": true, "d": true}), post)
+}
+
+func TestDFSCycle(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> a. Both
+	// nodes must be visited once.
+	g.Edge("a", "b")
+	g.Edge("b", "a")
+
+	var post []string
+	g.DFS("a", nil, func(k string) {
+		post = append(post, k)
+	})
+
+	if !reflect.DeepEqual(post, []string{"b", "a"}) {
+		t.Errorf("expected [b a], got %v", post)
+	}
+}
+
+func TestBFSLevels(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> d,
+	// a -> c, d -> a and an unreachable node e -> a.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("a", "c")
+	g.Edge("d", "a")
+	g.Edge("e", "a")
+
+	levels := g.BFSLevels("a")
+	expected := map[string]int{"a": 0, "b": 1, "c": 1, "d": 2}
+
+	if !reflect.DeepEqual(levels, expected) {
+		t.Errorf("expected %v, got %v", expected, levels)
+	}
+
+	if levels := g.BFSLevels("x"); levels != nil {
+		t.Errorf("expected nil, got %v", levels)
+	}
+}
+
+func TestReachableWithin(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> d
+	// and a -> x -> d.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("a", "x")
+	g.Edge("x", "d")
+
+	if !g.ReachableWithin("a", "d", 2) {
+		t.Errorf("expected d within 2 edges of a")
+	}
+
+	if g.ReachableWithin("a", "d", 1) {
+		t.Errorf("expected d not within 1 edge of a")
+	}
+
+	if g.ReachableWithin("b", "d", 1) || !g.ReachableWithin("b", "d", 2) {
+		t.Errorf("expected d exactly 2 edges from b")
+	}
+
+	if !g.ReachableWithin("a", "a", 0) || g.ReachableWithin("d", "a", 10) {
+		t.Errorf("expected a within 0 edges of itself, and not reachable from d")
+	}
+
+	if g.ReachableWithin("a", "z", 10) || g.ReachableWithin("z", "z", 10) {
+		t.Errorf("expected missing nodes not to be reachable")
+	}
+}
+
+func TestAtDistance(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> d,
+	// a -> x -> d, x -> y and d -> a.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("a", "x")
+	g.Edge("x", "d")
+	g.Edge("x", "y")
+	g.Edge("d", "a")
+
+	if keys := g.AtDistance("a", 2); !reflect.DeepEqual(keys, []string{"c", "d", "y"}) {
+		t.Errorf("expected [c d y], got %v", keys)
+	}
+
+	// The cycle back to a does not make a appear at a larger distance.
+	if keys := g.AtDistance("a", 0); !reflect.DeepEqual(keys, []string{"a"}) {
+		t.Errorf("expected [a], got %v", keys)
+	}
+
+	if keys := g.AtDistance("a", 3); len(keys) != 0 || keys == nil {
+		t.Errorf("expected an empty slice, got %v", keys)
+	}
+
+	if keys := g.AtDistance("z", 0); len(keys) != 0 || keys == nil {
+		t.Errorf("expected an empty slice, got %v", keys)
+	}
+}
+
+func TestNeighborhood(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> d,
+	// e -> b and e -> d. Within one hop of b are a, c and e, and within two
+	// hops also d.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("e", "b")
+	g.Edge("e", "d")
+
+	n := g.Neighborhood("b", 1)
+	expected := map[string]Edges[string]{
+		"a": {"b": true},
+		"b": {"c": true},
+		"c": {},
+		"e": {"b": true},
+	}
+
+	if !reflect.DeepEqual(n.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, n.nodes)
+	}
+
+	if n := g.Neighborhood("b", 2); len(n.nodes) != 5 || !n.HasEdge("e", "d") {
+		t.Errorf("expected the whole graph, got %v", n.nodes)
+	}
+
+	if n := g.Neighborhood("x", 2); len(n.nodes) != 0 {
+		t.Errorf("expected an empty graph, got %v", n.nodes)
+	}
+}
+
+func TestWalkDepth(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> d -> e,
+	// a -> c -> d and d -> a. The node d is reached through both b and c,
+	// and the edge d -> a is cut off because a is already on the path.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "d")
+	g.Edge("c", "d")
+	g.Edge("d", "e")
+	g.Edge("d", "a")
+
+	var visited []string
+	g.WalkDepth("a", 2, func(k string, depth int) bool {
+		visited = append(visited, fmt.Sprintf("%v%v", k, depth))
+		return true
+	})
+
+	expected := []string{"a0", "b1", "d2", "c1", "d2"}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("expected %v, got %v", expected, visited)
+	}
+
+	// Returning false prunes the nodes below b.
+	visited = nil
+	g.WalkDepth("a", 10, func(k string, depth int) bool {
+		visited = append(visited, fmt.Sprintf("%v%v", k, depth))
+		return k != "b"
+	})
+
+	expected = []string{"a0", "b1", "c1", "d2", "e3"}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("expected %v, got %v", expected, visited)
+	}
+
+	g.WalkDepth("x", 10, func(k string, depth int) bool {
+		t.Errorf("expected no visits, got %v", k)
+		return true
+	})
+}
+
+func TestMaximalChains(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> d,
+	// a -> c -> d, d -> e, x -> e and an isolated node y.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "d")
+	g.Edge("c", "d")
+	g.Edge("d", "e")
+	g.Edge("x", "e")
+	g.Node("y")
+
+	chains, err := g.MaximalChains()
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := [][]string{
+		{"a", "b", "d", "e"},
+		{"a", "c", "d", "e"},
+		{"x", "e"},
+		{"y"},
+	}
+	if !reflect.DeepEqual(chains, expected) {
+		t.Errorf("expected %v, got %v", expected, chains)
+	}
+
+	g.Edge("e", "a")
+
+	if _, err := g.MaximalChains(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+}
+
+func TestPathsFromRoots(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> d,
+	// a -> c -> d, x -> d, d -> t and t -> d. The cycle between d and t is
+	// only followed once.
+	g.Edge("a", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "d")
+	g.Edge("c", "d")
+	g.Edge("x", "d")
+	g.Edge("d", "t")
+	g.Edge("t", "d")
+
+	expected := [][]string{
+		{"a", "b", "d", "t"},
+		{"a", "c", "d", "t"},
+		{"x", "d", "t"},
+	}
+	if paths := g.PathsFromRoots("t"); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %v, got %v", expected, paths)
+	}
+
+	if paths := g.PathsFromRoots("a"); !reflect.DeepEqual(paths, [][]string{{"a"}}) {
+		t.Errorf("expected [[a]], got %v", paths)
+	}
+
+	if paths := g.PathsFromRoots("z"); paths != nil {
+		t.Errorf("expected nil, got %v", paths)
+	}
+}
+
+func TestSpanningTree(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: r -> a -> c,
+	// r -> b -> c, c -> d, d -> r and x -> c. The node c is reached from a
+	// first, and x cannot be reached.
+	g.Edge("r", "a")
+	g.Edge("r", "b")
+	g.Edge("a", "c")
+	g.Edge("b", "c")
+	g.Edge("c", "d")
+	g.Edge("d", "r")
+	g.Edge("x", "c")
+
+	tree, unreached := g.SpanningTree("r")
+
+	expected := map[string]Edges[string]{
+		"r": {"a": true, "b": true},
+		"a": {"c": true},
+		"b": {},
+		"c": {"d": true},
+		"d": {},
+	}
+
+	if !reflect.DeepEqual(tree.nodes, expected) {
+		t.Errorf("expected %v, got %v", expected, tree.nodes)
+	}
+
+	if !reflect.DeepEqual(unreached, []string{"x"}) {
+		t.Errorf("expected [x], got %v", unreached)
+	}
+
+	if tree, unreached := g.SpanningTree("z"); tree != nil || unreached != nil {
+		t.Errorf("expected nil, got %v and %v", tree, unreached)
+	}
+}
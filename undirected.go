@@ -0,0 +1,235 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import "iter"
+
+// IsArticulationPoint reports whether removing a node would increase the
+// number of connected components of the graph, when the direction of the
+// edges is ignored. Such a node is the only connection between the nodes on
+// either side of it. It returns false if the node does not exist.
+//
+// IsArticulationPoint finds all articulation points with a depth-first search,
+// which takes O(n) time for n = [number of nodes] + [number of edges].
+func (g *Graph[Key]) IsArticulationPoint(key Key) bool {
+	key = g.key(key)
+	if _, ok := g.nodes[key]; !ok {
+		return false
+	}
+
+	return g.articulationPoints()[key]
+}
+
+// Bridges returns the edges whose removal would increase the number of
+// connected components of the graph, when the direction of the edges is
+// ignored, in a deterministic order. Such an edge is the only connection
+// between the nodes on either side of it. If two nodes have edges in both
+// directions, they only count as one connection, and both edges are returned.
+// Self-loops are never bridges.
+//
+// Bridges finds all bridges with a depth-first search, like
+// IsArticulationPoint, which takes O(n) time for n = [number of nodes] +
+// [number of edges].
+func (g *Graph[Key]) Bridges() [][2]Key {
+	// https://en.wikipedia.org/wiki/Bridge_(graph_theory)#Tarjan's_bridge-finding_algorithm
+
+	u := g.undirected()
+
+	// The depth of every visited node in the depth-first search tree, and the
+	// lowest depth that can be reached from its subtree using a single edge
+	// that is not part of the tree.
+	depth := make(map[Key]int, len(u))
+	low := make(map[Key]int, len(u))
+
+	var bridges [][2]Key
+
+	var visit func(n, parent Key, d int)
+	visit = func(n, parent Key, d int) {
+		depth[n] = d
+		low[n] = d
+
+		for m := range u[n] {
+			if _, ok := depth[m]; !ok {
+				visit(m, n, d+1)
+				low[n] = min(low[n], low[m])
+
+				// If the subtree of m cannot reach n or above without the
+				// edge between n and m, that edge is a bridge.
+				if low[m] > d {
+					if g.nodes[n][m] {
+						bridges = append(bridges, [2]Key{n, m})
+					}
+					if g.nodes[m][n] {
+						bridges = append(bridges, [2]Key{m, n})
+					}
+				}
+			} else if m != parent {
+				low[n] = min(low[n], depth[m])
+			}
+		}
+	}
+
+	for k := range u {
+		if _, ok := depth[k]; !ok {
+			visit(k, k, 0)
+		}
+	}
+
+	sortEdges(bridges)
+
+	return bridges
+}
+
+// undirected returns the edges of the graph in both directions, without
+// self-loops.
+func (g *Graph[Key]) undirected() map[Key]Edges[Key] {
+	u := make(map[Key]Edges[Key], len(g.nodes))
+	for k := range g.nodes {
+		u[k] = make(Edges[Key])
+	}
+
+	for from, e := range g.nodes {
+		for to := range e {
+			if from != to {
+				u[from].add(to)
+				u[to].add(from)
+			}
+		}
+	}
+
+	return u
+}
+
+// articulationPoints returns the articulation points of the graph, when the
+// direction of the edges is ignored.
+func (g *Graph[Key]) articulationPoints() map[Key]bool {
+	// https://en.wikipedia.org/wiki/Biconnected_component#Algorithms
+
+	u := g.undirected()
+
+	// The depth of every visited node in the depth-first search tree, and the
+	// lowest depth that can be reached from its subtree using a single edge
+	// that is not part of the tree.
+	depth := make(map[Key]int, len(u))
+	low := make(map[Key]int, len(u))
+
+	points := make(map[Key]bool)
+
+	var visit func(n, parent Key, d int)
+	visit = func(n, parent Key, d int) {
+		depth[n] = d
+		low[n] = d
+
+		children := 0
+		for m := range u[n] {
+			if _, ok := depth[m]; !ok {
+				children++
+				visit(m, n, d+1)
+				low[n] = min(low[n], low[m])
+
+				// If the subtree of m cannot reach above n, removing n
+				// disconnects it. The root is handled separately below.
+				if d > 0 && low[m] >= d {
+					points[n] = true
+				}
+			} else if m != parent {
+				low[n] = min(low[n], depth[m])
+			}
+		}
+
+		// The root is an articulation point if it has more than one subtree.
+		if d == 0 && children > 1 {
+			points[n] = true
+		}
+	}
+
+	for k := range u {
+		if _, ok := depth[k]; !ok {
+			visit(k, k, 0)
+		}
+	}
+
+	return points
+}
+
+// connected returns the connected components of the graph, when the direction
+// of the edges is ignored, leaving out the nodes in exclude. The nodes of every
+// component are in the order of compareKeys, and the components are ordered by
+// their first node.
+func (g *Graph[Key]) connected(exclude map[Key]bool) [][]Key {
+	u := g.undirected()
+	seen := make(map[Key]bool, len(u))
+
+	var components [][]Key
+	for _, k := range g.keys() {
+		if seen[k] || exclude[k] {
+			continue
+		}
+
+		seen[k] = true
+		c := []Key{k}
+		for i := 0; i < len(c); i++ {
+			for m := range u[c[i]] {
+				if !seen[m] && !exclude[m] {
+					seen[m] = true
+					c = append(c, m)
+				}
+			}
+		}
+		sortKeys(c)
+		components = append(components, c)
+	}
+
+	return components
+}
+
+// ComponentsSeq returns an iterator over the connected components of the
+// graph, when the direction of the edges is ignored. Every component is only
+// searched when the previous one has been handled, so iteration can stop as
+// soon as a suitable component is found. The nodes of every component are in
+// a deterministic order, and the components are ordered by their first node.
+// Every yielded slice is a new slice, which the caller may keep. The iterator
+// must not be used while the graph changes.
+func (g *Graph[Key]) ComponentsSeq() iter.Seq[[]Key] {
+	return func(yield func([]Key) bool) {
+		u := g.undirected()
+		seen := make(map[Key]bool, len(u))
+
+		for _, k := range g.keys() {
+			if seen[k] {
+				continue
+			}
+
+			seen[k] = true
+			c := []Key{k}
+			for i := 0; i < len(c); i++ {
+				for m := range u[c[i]] {
+					if !seen[m] {
+						seen[m] = true
+						c = append(c, m)
+					}
+				}
+			}
+			sortKeys(c)
+
+			if !yield(c) {
+				return
+			}
+		}
+	}
+}
+
+// IsSymmetric reports whether the graph has an edge b -> a for every edge
+// a -> b, so it is equal to its reverse and represents an undirected graph.
+// Self-loops are symmetric on their own. An empty graph is symmetric.
+func (g *Graph[Key]) IsSymmetric() bool {
+	for from, e := range g.nodes {
+		for to := range e {
+			if !g.nodes[to][from] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
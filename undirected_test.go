@@ -0,0 +1,109 @@
+// © 2025 Rolf van de Krol <rolf@vandekrol.xyz>
+
+package graph
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestIsArticulationPoint(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, b -> c,
+	// c -> a, c -> d, e -> d and an isolated node f. Ignoring directions, a,
+	// b and c form a cycle, and c and d connect it to e.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "a")
+	g.Edge("c", "d")
+	g.Edge("e", "d")
+	g.Node("f")
+
+	for key, expected := range map[string]bool{
+		"a": false,
+		"b": false,
+		"c": true,
+		"d": true,
+		"e": false,
+		"f": false,
+		"x": false,
+	} {
+		if actual := g.IsArticulationPoint(key); actual != expected {
+			t.Errorf("expected %v for %v, got %v", expected, key, actual)
+		}
+	}
+}
+
+func TestBridges(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b -> c -> a,
+	// c -> d, d -> e, e -> d, x -> x and an isolated node y. The triangle
+	// has no bridges, while c -> d and both edges between d and e are the
+	// only connections of d and e.
+	g.Edge("a", "b")
+	g.Edge("b", "c")
+	g.Edge("c", "a")
+	g.Edge("c", "d")
+	g.Edge("d", "e")
+	g.Edge("e", "d")
+	g.Edge("x", "x")
+	g.Node("y")
+
+	expected := [][2]string{{"c", "d"}, {"d", "e"}, {"e", "d"}}
+	if bridges := g.Bridges(); !reflect.DeepEqual(bridges, expected) {
+		t.Errorf("expected %v, got %v", expected, bridges)
+	}
+}
+
+func TestComponentsSeq(t *testing.T) {
+	g := New[string]()
+
+	// We construct a graph with the following structure: a -> b, c -> b,
+	// x -> y and an isolated node m.
+	g.Edge("a", "b")
+	g.Edge("c", "b")
+	g.Edge("x", "y")
+	g.Node("m")
+
+	expected := [][]string{{"a", "b", "c"}, {"m"}, {"x", "y"}}
+	if components := slices.Collect(g.ComponentsSeq()); !reflect.DeepEqual(components, expected) {
+		t.Errorf("expected %v, got %v", expected, components)
+	}
+
+	// Stopping early must be possible.
+	for c := range g.ComponentsSeq() {
+		if !reflect.DeepEqual(c, expected[0]) {
+			t.Errorf("expected %v, got %v", expected[0], c)
+		}
+		break
+	}
+}
+
+func TestIsSymmetric(t *testing.T) {
+	g := New[string]()
+
+	if !g.IsSymmetric() {
+		t.Error("expected an empty graph to be symmetric")
+	}
+
+	// We construct a graph with the following structure: a -> b -> a,
+	// b -> c -> b and c -> c.
+	g.Edge("a", "b")
+	g.Edge("b", "a")
+	g.Edge("b", "c")
+	g.Edge("c", "b")
+	g.Edge("c", "c")
+
+	if !g.IsSymmetric() {
+		t.Error("expected a symmetric graph")
+	}
+
+	g.Edge("a", "c")
+
+	if g.IsSymmetric() {
+		t.Error("expected no symmetric graph with a -> c")
+	}
+}